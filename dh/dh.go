@@ -0,0 +1,81 @@
+package dh
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+)
+
+var (
+	bigOne = big.NewInt(1)
+	bigTwo = big.NewInt(2)
+)
+
+// Group is a prime p and a generator g of a subgroup of order q in Z_p*.
+type Group struct {
+	P, G, Q *big.Int
+}
+
+func mustDecimal(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("Invalid decimal constant")
+	}
+	return n
+}
+
+// Challenge57Group is the group from challenge 57. (p-1)/q has plenty of
+// small factors.
+var Challenge57Group = Group{
+	P: mustDecimal("7199773997391911030609999317773941274322764333428698921736339643928346453700085358802973900485592910475480089726140708102474957429903531369589969318716771"),
+	G: mustDecimal("4565356397095740655436854503483826832136106141639563487732438195343690437606117828318042418238184896212352329118608100083187535033402010599512641674644143"),
+	Q: mustDecimal("236234353446506858198510045061214171961"),
+}
+
+type PrivateKey struct {
+	Group
+	X *big.Int
+	Y *big.Int
+}
+
+func GenerateKey(group Group) (*PrivateKey, error) {
+	x, err := rand.Int(rand.Reader, new(big.Int).Sub(group.Q, bigOne))
+	if err != nil {
+		return nil, err
+	}
+	x.Add(x, bigOne)
+
+	return &PrivateKey{
+		Group: group,
+		X:     x,
+		Y:     new(big.Int).Exp(group.G, x, group.P),
+	}, nil
+}
+
+// SharedSecret computes other^x mod p without checking other is in the
+// subgroup, which is what the confinement attack relies on.
+func (priv *PrivateKey) SharedSecret(other *big.Int) *big.Int {
+	return new(big.Int).Exp(other, priv.X, priv.P)
+}
+
+// MAC is HMAC-SHA256 keyed with SHA-256 of the shared secret.
+func MAC(secret *big.Int, msg []byte) []byte {
+	key := sha256.Sum256(secret.Bytes())
+	h := hmac.New(sha256.New, key[:])
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// MACOracle takes the attacker's public key and returns a message and its
+// MAC under the shared secret.
+type MACOracle func(h *big.Int) ([]byte, []byte)
+
+// NewBob returns an oracle that answers with a fixed message MACed under
+// the secret it shares with whatever public key it is given.
+func NewBob(priv *PrivateKey) MACOracle {
+	msg := []byte("crazy flamboyant for the rap enjoyment")
+	return func(h *big.Int) ([]byte, []byte) {
+		return msg, MAC(priv.SharedSecret(h), msg)
+	}
+}
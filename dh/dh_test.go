@@ -0,0 +1,28 @@
+package dh
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup(t *testing.T) {
+	g := Challenge57Group
+	pMinusOne := new(big.Int).Sub(g.P, bigOne)
+	assert.Zero(t, new(big.Int).Mod(pMinusOne, g.Q).Sign())
+	assert.Equal(t, bigOne, new(big.Int).Exp(g.G, g.Q, g.P))
+}
+
+func TestSharedSecret(t *testing.T) {
+	alice, err := GenerateKey(Challenge57Group)
+	require.NoError(t, err)
+	bob, err := GenerateKey(Challenge57Group)
+	require.NoError(t, err)
+
+	s1 := alice.SharedSecret(bob.Y)
+	s2 := bob.SharedSecret(alice.Y)
+	assert.Equal(t, s1, s2)
+	assert.Equal(t, MAC(s1, []byte("hi")), MAC(s2, []byte("hi")))
+}
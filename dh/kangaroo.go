@@ -0,0 +1,196 @@
+package dh
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/josh-keller/cryptopals/rsa"
+)
+
+var ErrKangarooEscaped = errors.New("dh: wild kangaroo passed the trap without landing in it")
+
+// Challenge58Group has (p-1)/q with small factors that only cover about 89 of
+// q's 128 bits, leaving the rest for the kangaroo.
+var Challenge58Group = Group{
+	P: mustDecimal("11470374874925275658116663507232161402086650258453896274534991676898999262641581519101074740642369848233294239851519212341844337347119899874391456329785623"),
+	G: mustDecimal("622952335333961296978159266084741085889881358738459939978290179936063635566740258555167783009058567397963466103140082647486611657350811560630587013183357"),
+	Q: mustDecimal("335062023296420808191071248367701059461"),
+}
+
+type KangarooOptions struct {
+	// K is the number of jump sizes, which are 2^0 through 2^(K-1). Zero
+	// picks the smallest K whose mean jump reaches MeanJumpScale*sqrt(b-a).
+	K int
+	// MeanJumpScale sets the target mean jump when K is zero. Defaults to 0.5.
+	MeanJumpScale float64
+	// TameJumps is how far the tame kangaroo runs, in multiples of the mean
+	// jump. Defaults to 4.
+	TameJumps float64
+	// Jump maps an element to a jump index in [0, k). Defaults to y mod k.
+	Jump func(y *big.Int, k int) int
+}
+
+// DefaultJump is y mod k.
+func DefaultJump(y *big.Int, k int) int {
+	words := y.Bits()
+	if len(words) == 0 {
+		return 0
+	}
+	return int(uint64(words[0]) % uint64(k))
+}
+
+// KangarooPlan is everything a walk needs once the options are filled in.
+type KangarooPlan struct {
+	Jump func(y *big.Int, k int) int
+	// Sizes are the jump distances, indexed by Jump's result
+	Sizes []uint64
+	// TameJumps is how many jumps the tame kangaroo makes
+	TameJumps uint64
+}
+
+// Plan applies the defaults and picks the jump sizes for an interval, so
+// walks over other groups match this package's.
+func (opts KangarooOptions) Plan(a, b uint64) (*KangarooPlan, error) {
+	if b < a {
+		return nil, errors.New("dh: empty interval")
+	}
+	if opts.MeanJumpScale == 0 {
+		opts.MeanJumpScale = 0.5
+	}
+	if opts.TameJumps == 0 {
+		opts.TameJumps = 4
+	}
+	if opts.Jump == nil {
+		opts.Jump = DefaultJump
+	}
+
+	k := opts.K
+	if k == 0 {
+		target := opts.MeanJumpScale * math.Sqrt(float64(b-a))
+		for k = 1; float64(uint64(1)<<k-1)/float64(k) < target && k < 63; k++ {
+		}
+	}
+
+	sizes := make([]uint64, k)
+	mean := 0.0
+	for i := range sizes {
+		sizes[i] = 1 << i
+		mean += float64(sizes[i])
+	}
+	mean /= float64(k)
+
+	return &KangarooPlan{
+		Jump:      opts.Jump,
+		Sizes:     sizes,
+		TameJumps: uint64(opts.TameJumps * mean),
+	}, nil
+}
+
+// KangarooAttempts is how many sets of jumps to try before giving up.
+const KangarooAttempts = 4
+
+// RetryOptions gives a different jump function for each attempt. The walk is
+// deterministic, so if the wild kangaroo escapes the only way to try again
+// is with different jumps.
+func RetryOptions(attempt int) KangarooOptions {
+	opts := KangarooOptions{}
+	if attempt > 0 {
+		opts.Jump = func(y *big.Int, k int) int {
+			return DefaultJump(y, k+attempt) % k
+		}
+	}
+	return opts
+}
+
+// Kangaroo finds x in [a, b] with g^x = y mod p using Pollard's lambda
+// method and the default options.
+func Kangaroo(g, y, p *big.Int, a, b uint64) (*big.Int, error) {
+	return KangarooWithOptions(g, y, p, a, b, KangarooOptions{})
+}
+
+// KangarooWithOptions runs a tame kangaroo from g^b and records where it
+// stops, then a wild kangaroo from y. Both take jumps determined by where
+// they land, so once the wild one lands on a spot the tame one visited it
+// follows the same path into the trap, and the distances give x.
+func KangarooWithOptions(g, y, p *big.Int, a, b uint64, opts KangarooOptions) (*big.Int, error) {
+	plan, err := opts.Plan(a, b)
+	if err != nil {
+		return nil, err
+	}
+	powers := make([]*big.Int, len(plan.Sizes))
+	for i, size := range plan.Sizes {
+		powers[i] = new(big.Int).Exp(g, new(big.Int).SetUint64(size), p)
+	}
+	k := len(plan.Sizes)
+
+	xT := uint64(0)
+	yT := new(big.Int).Exp(g, new(big.Int).SetUint64(b), p)
+	for i := uint64(0); i < plan.TameJumps; i++ {
+		j := plan.Jump(yT, k)
+		xT += plan.Sizes[j]
+		yT.Mul(yT, powers[j])
+		yT.Mod(yT, p)
+	}
+
+	xW := uint64(0)
+	yW := new(big.Int).Set(y)
+	limit := b - a + xT
+	for xW < limit {
+		j := plan.Jump(yW, k)
+		xW += plan.Sizes[j]
+		yW.Mul(yW, powers[j])
+		yW.Mod(yW, p)
+
+		if yW.Cmp(yT) == 0 {
+			x := new(big.Int).SetUint64(b)
+			x.Add(x, new(big.Int).SetUint64(xT))
+			return x.Sub(x, new(big.Int).SetUint64(xW)), nil
+		}
+	}
+
+	return nil, ErrKangarooEscaped
+}
+
+// SubgroupKangarooAttack recovers Bob's whole private key when the small
+// factors of (p-1)/q don't cover q. The subgroup attack gives x = n mod r,
+// so x = n + m*r, and y * g^-n = (g^r)^m with m at most q/r, which the
+// kangaroo can find.
+func SubgroupKangarooAttack(group Group, bob MACOracle, y *big.Int, bound uint64) (*big.Int, error) {
+	n, r, err := SmallSubgroupAttack(group, bob, bound)
+	if err != nil {
+		return nil, err
+	}
+	if r.Cmp(group.Q) > 0 {
+		return n, nil
+	}
+
+	p := group.P
+	gInvN, err := rsa.InvMod(new(big.Int).Exp(group.G, n, p), p)
+	if err != nil {
+		return nil, err
+	}
+	yPrime := new(big.Int).Mul(y, gInvN)
+	yPrime.Mod(yPrime, p)
+	gPrime := new(big.Int).Exp(group.G, r, p)
+
+	upper := new(big.Int).Div(new(big.Int).Sub(group.Q, bigOne), r)
+	if !upper.IsUint64() {
+		return nil, errors.New("dh: interval too large for the kangaroo")
+	}
+
+	for attempt := 0; attempt < KangarooAttempts; attempt++ {
+		m, err := KangarooWithOptions(gPrime, yPrime, p, 0, upper.Uint64(), RetryOptions(attempt))
+		if errors.Is(err, ErrKangarooEscaped) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		x := m.Mul(m, r)
+		return x.Add(x, n), nil
+	}
+
+	return nil, ErrKangarooEscaped
+}
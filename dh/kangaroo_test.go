@@ -0,0 +1,106 @@
+package dh
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKangaroo(t *testing.T) {
+	group := Challenge58Group
+
+	t.Run("Challenge 58 20 bit key", func(t *testing.T) {
+		y := mustDecimal("7760073848032689505395005705677365876654629189298052775754597607446617558600394076764814236081991643094239886772481052254010323780165093955236429914607119")
+		x, err := Kangaroo(group.G, y, group.P, 0, 1<<20)
+		require.NoError(t, err)
+		assert.Equal(t, y, new(big.Int).Exp(group.G, x, group.P))
+	})
+
+	t.Run("Random keys in an interval", func(t *testing.T) {
+		a, b := uint64(1<<30), uint64(1<<30+1<<24)
+		found := 0
+		for i := 0; i < 5; i++ {
+			offset, err := rand.Int(rand.Reader, big.NewInt(int64(b-a)))
+			require.NoError(t, err)
+			secret := offset.Add(offset, new(big.Int).SetUint64(a))
+			y := new(big.Int).Exp(group.G, secret, group.P)
+
+			x, err := Kangaroo(group.G, y, group.P, a, b)
+			if err != nil {
+				assert.ErrorIs(t, err, ErrKangarooEscaped)
+				continue
+			}
+			found++
+			assert.Equal(t, secret, x)
+		}
+		assert.Greater(t, found, 2)
+	})
+
+	t.Run("Custom jump function", func(t *testing.T) {
+		secret := big.NewInt(123456)
+		y := new(big.Int).Exp(group.G, secret, group.P)
+		x, err := KangarooWithOptions(group.G, y, group.P, 0, 1<<20, KangarooOptions{
+			K:         12,
+			TameJumps: 8,
+			Jump: func(y *big.Int, k int) int {
+				return int(new(big.Int).Mod(y, big.NewInt(int64(k))).Int64())
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, secret, x)
+	})
+}
+
+func TestKangarooPlan(t *testing.T) {
+	plan, err := KangarooOptions{}.Plan(0, 1<<20)
+	require.NoError(t, err)
+	// Mean of 2^0..2^(k-1) first reaches 0.5*sqrt(2^20) = 512 at k = 13
+	assert.Len(t, plan.Sizes, 13)
+	for i, size := range plan.Sizes {
+		assert.Equal(t, uint64(1)<<i, size)
+	}
+	// Four mean jumps of (2^13 - 1) / 13 each
+	assert.Equal(t, uint64(2520), plan.TameJumps)
+
+	plan, err = KangarooOptions{K: 5, TameJumps: 2}.Plan(0, 1<<20)
+	require.NoError(t, err)
+	assert.Len(t, plan.Sizes, 5)
+	assert.Equal(t, uint64(12), plan.TameJumps)
+
+	_, err = KangarooOptions{}.Plan(2, 1)
+	assert.Error(t, err)
+
+	// Each retry needs a different walk to have a chance of landing
+	y := big.NewInt(0x1234567)
+	jumps := map[int]bool{}
+	for attempt := 0; attempt < KangarooAttempts; attempt++ {
+		plan, err := RetryOptions(attempt).Plan(0, 1<<20)
+		require.NoError(t, err)
+		jumps[plan.Jump(y, len(plan.Sizes))] = true
+	}
+	assert.Greater(t, len(jumps), 1)
+}
+
+func TestSubgroupKangarooAttack(t *testing.T) {
+	priv, err := GenerateKey(Challenge58Group)
+	require.NoError(t, err)
+
+	x, err := SubgroupKangarooAttack(Challenge58Group, NewBob(priv), priv.Y, 1<<16)
+	require.NoError(t, err)
+	assert.Equal(t, priv.X, x)
+}
+
+func benchmarkKangaroo(b *testing.B, bits uint) {
+	group := Challenge58Group
+	for i := 0; i < b.N; i++ {
+		secret, _ := rand.Int(rand.Reader, new(big.Int).Lsh(bigOne, bits))
+		y := new(big.Int).Exp(group.G, secret, group.P)
+		Kangaroo(group.G, y, group.P, 0, 1<<bits)
+	}
+}
+
+func BenchmarkKangaroo20(b *testing.B) { benchmarkKangaroo(b, 20) }
+func BenchmarkKangaroo40(b *testing.B) { benchmarkKangaroo(b, 40) }
@@ -0,0 +1,116 @@
+package dh
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/josh-keller/cryptopals/rsa"
+)
+
+// SmallFactors returns the distinct prime factors of n up to bound, found
+// by trial division.
+func SmallFactors(n *big.Int, bound uint64) []uint64 {
+	factors := []uint64{}
+	rem := new(big.Int).Set(n)
+	d, mod := new(big.Int), new(big.Int)
+
+	for f := uint64(2); f <= bound; f++ {
+		d.SetUint64(f)
+		if mod.Mod(rem, d).Sign() != 0 {
+			continue
+		}
+		factors = append(factors, f)
+		for mod.Mod(rem, d).Sign() == 0 {
+			rem.Div(rem, d)
+		}
+	}
+
+	return factors
+}
+
+// ElementOfOrder finds an element of order r in Z_p*, where r is prime and
+// divides p-1, by raising random elements to (p-1)/r.
+func ElementOfOrder(p *big.Int, r uint64) (*big.Int, error) {
+	pMinusOne := new(big.Int).Sub(p, bigOne)
+	bigR := new(big.Int).SetUint64(r)
+	exp, rem := new(big.Int).DivMod(pMinusOne, bigR, new(big.Int))
+	if rem.Sign() != 0 {
+		return nil, errors.New("dh: r does not divide p-1")
+	}
+
+	for {
+		a, err := rand.Int(rand.Reader, new(big.Int).Sub(p, bigTwo))
+		if err != nil {
+			return nil, err
+		}
+		a.Add(a, bigTwo)
+		h := a.Exp(a, exp, p)
+		if h.Cmp(bigOne) != 0 {
+			return h, nil
+		}
+	}
+}
+
+// recoverResidue finds b < r such that h^b gives the MAC Bob returned, which
+// means Bob's key is b mod r.
+func recoverResidue(h *big.Int, r uint64, p *big.Int, msg, mac []byte) (uint64, error) {
+	k := big.NewInt(1)
+	for b := uint64(0); b < r; b++ {
+		if hmac.Equal(MAC(k, msg), mac) {
+			return b, nil
+		}
+		k.Mul(k, h)
+		k.Mod(k, p)
+	}
+	return 0, errors.New("dh: no residue matches MAC")
+}
+
+// SmallSubgroupAttack recovers Bob's private key modulo the small factors
+// of (p-1)/q up to bound. Bob never checks that our public key is in the
+// subgroup of order q, so sending him an element of small order r confines
+// the shared secret to r values, and the MAC tells us which one. The residues
+// are combined with the CRT. It stops once the modulus exceeds q, in which
+// case the residue is the key itself, otherwise it returns what it has.
+func SmallSubgroupAttack(group Group, bob MACOracle, bound uint64) (*big.Int, *big.Int, error) {
+	j := new(big.Int).Div(new(big.Int).Sub(group.P, bigOne), group.Q)
+	residues := []*big.Int{}
+	moduli := []*big.Int{}
+	product := big.NewInt(1)
+
+	for _, r := range SmallFactors(j, bound) {
+		bigR := new(big.Int).SetUint64(r)
+		// Factors that share something with q would confuse the CRT
+		if new(big.Int).Mod(group.Q, bigR).Sign() == 0 {
+			continue
+		}
+
+		h, err := ElementOfOrder(group.P, r)
+		if err != nil {
+			return nil, nil, err
+		}
+		msg, mac := bob(h)
+		b, err := recoverResidue(h, r, group.P, msg, mac)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		residues = append(residues, new(big.Int).SetUint64(b))
+		moduli = append(moduli, bigR)
+		product.Mul(product, bigR)
+		if product.Cmp(group.Q) > 0 {
+			break
+		}
+	}
+
+	if len(moduli) == 0 {
+		return nil, nil, errors.New("dh: no usable small factors")
+	}
+
+	x, err := rsa.CRT(residues, moduli)
+	if err != nil {
+		return nil, nil, err
+	}
+	return x, product, nil
+}
@@ -0,0 +1,37 @@
+package dh
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSmallFactors(t *testing.T) {
+	assert.Equal(t, []uint64{2, 3, 5}, SmallFactors(big.NewInt(360), 100))
+	assert.Equal(t, []uint64{2, 3}, SmallFactors(big.NewInt(2*3*101), 100))
+}
+
+func TestElementOfOrder(t *testing.T) {
+	p := Challenge57Group.P
+	for _, r := range []uint64{2, 5, 109, 7963} {
+		h, err := ElementOfOrder(p, r)
+		require.NoError(t, err)
+		assert.NotEqual(t, bigOne, h)
+		assert.Equal(t, bigOne, new(big.Int).Exp(h, new(big.Int).SetUint64(r), p))
+	}
+
+	_, err := ElementOfOrder(p, 7)
+	assert.Error(t, err)
+}
+
+func TestSmallSubgroupAttack(t *testing.T) {
+	priv, err := GenerateKey(Challenge57Group)
+	require.NoError(t, err)
+
+	x, modulus, err := SmallSubgroupAttack(Challenge57Group, NewBob(priv), 1<<16)
+	require.NoError(t, err)
+	assert.True(t, modulus.Cmp(Challenge57Group.Q) > 0)
+	assert.Equal(t, priv.X, x)
+}
@@ -0,0 +1,158 @@
+package dsa
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"errors"
+	"math/big"
+
+	"github.com/josh-keller/cryptopals/rsa"
+)
+
+var bigOne = big.NewInt(1)
+
+type Parameters struct {
+	P, Q, G *big.Int
+}
+
+type PublicKey struct {
+	Parameters
+	Y *big.Int
+}
+
+type PrivateKey struct {
+	PublicKey
+	X *big.Int
+}
+
+type Signature struct {
+	R, S *big.Int
+}
+
+func mustHex(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("Invalid hex constant")
+	}
+	return n
+}
+
+// ChallengeParameters are the domain parameters given in challenge 43.
+var ChallengeParameters = Parameters{
+	P: mustHex("800000000000000089e1855218a0e7dac38136ffafa72eda7" +
+		"859f2171e25e65eac698c1702578b07dc2a1076da241c76c6" +
+		"2d374d8389ea5aeffd3226a0530cc565f3bf6b50929139ebe" +
+		"ac04f48c3c84afb796d61e5a4f9a8fda812ab59494232c7d2" +
+		"b4deb50aa18ee9e132bfa85ac4374d7f9091abc3d015efc87" +
+		"1a584471bb1"),
+	Q: mustHex("f4f47f05794b256174bba6e9b396a7707e563c5b"),
+	G: mustHex("5958c9d3898b224b12672c0b98e06c60df923cb8bc999d119" +
+		"458fef538b8fa4046c8db53039db620c094c9fa077ef389b5" +
+		"322a559946a71903f990f1f7e0e025e2d7f7cf494aff1a047" +
+		"0f5b64c36b625a097f1651fe775323556fe00b3608c887892" +
+		"878480e99041be601a62166ca6894bdd41a7054ec89f756ba" +
+		"9fc95302291"),
+}
+
+// HashMessage returns SHA-1(msg) as an integer, which is H(m) in the
+// signing equations.
+func HashMessage(msg []byte) *big.Int {
+	digest := sha1.Sum(msg)
+	return new(big.Int).SetBytes(digest[:])
+}
+
+// randomScalar picks a value in [1, q-1].
+func randomScalar(q *big.Int) (*big.Int, error) {
+	k, err := rand.Int(rand.Reader, new(big.Int).Sub(q, bigOne))
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, bigOne), nil
+}
+
+func GenerateKey(params Parameters) (*PrivateKey, error) {
+	x, err := randomScalar(params.Q)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{
+		PublicKey: PublicKey{
+			Parameters: params,
+			Y:          new(big.Int).Exp(params.G, x, params.P),
+		},
+		X: x,
+	}, nil
+}
+
+func Sign(priv *PrivateKey, msg []byte) (*Signature, error) {
+	h := HashMessage(msg)
+	for {
+		k, err := randomScalar(priv.Q)
+		if err != nil {
+			return nil, err
+		}
+		sig, err := SignWithNonce(priv, h, k)
+		if err == nil {
+			return sig, nil
+		}
+	}
+}
+
+// SignWithNonce signs the hash h using the caller's choice of k. It is only
+// safe if k is secret, random and never reused.
+func SignWithNonce(priv *PrivateKey, h, k *big.Int) (*Signature, error) {
+	q := priv.Q
+	r := new(big.Int).Exp(priv.G, k, priv.P)
+	r.Mod(r, q)
+	if r.Sign() == 0 {
+		return nil, errors.New("dsa: r is zero, choose another k")
+	}
+
+	kInv, err := rsa.InvMod(k, q)
+	if err != nil {
+		return nil, err
+	}
+	s := new(big.Int).Mul(priv.X, r)
+	s.Add(s, h)
+	s.Mul(s, kInv)
+	s.Mod(s, q)
+	if s.Sign() == 0 {
+		return nil, errors.New("dsa: s is zero, choose another k")
+	}
+
+	return &Signature{R: r, S: s}, nil
+}
+
+func Verify(pub *PublicKey, msg []byte, sig *Signature) bool {
+	return VerifyHash(pub, HashMessage(msg), sig)
+}
+
+func VerifyHash(pub *PublicKey, h *big.Int, sig *Signature) bool {
+	q := pub.Q
+	if sig.R.Sign() <= 0 || sig.R.Cmp(q) >= 0 || sig.S.Sign() <= 0 || sig.S.Cmp(q) >= 0 {
+		return false
+	}
+	return verify(pub, h, sig)
+}
+
+// verify is the verification equation on its own, with no range checks on r
+// or s.
+func verify(pub *PublicKey, h *big.Int, sig *Signature) bool {
+	q, p := pub.Q, pub.P
+	w, err := rsa.InvMod(sig.S, q)
+	if err != nil {
+		return false
+	}
+	u1 := new(big.Int).Mul(h, w)
+	u1.Mod(u1, q)
+	u2 := new(big.Int).Mul(sig.R, w)
+	u2.Mod(u2, q)
+
+	v := new(big.Int).Exp(pub.G, u1, p)
+	v.Mul(v, new(big.Int).Exp(pub.Y, u2, p))
+	v.Mod(v, p)
+	v.Mod(v, q)
+
+	return v.Cmp(new(big.Int).Mod(sig.R, q)) == 0
+}
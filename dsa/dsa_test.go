@@ -0,0 +1,35 @@
+package dsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDSA(t *testing.T) {
+	priv, err := GenerateKey(ChallengeParameters)
+	require.NoError(t, err)
+	msg := []byte("Sign me")
+
+	t.Run("Sign and verify", func(t *testing.T) {
+		sig, err := Sign(priv, msg)
+		require.NoError(t, err)
+		assert.True(t, Verify(&priv.PublicKey, msg, sig))
+	})
+
+	t.Run("Reject tampered message and signature", func(t *testing.T) {
+		sig, err := Sign(priv, msg)
+		require.NoError(t, err)
+		assert.False(t, Verify(&priv.PublicKey, []byte("Sign you"), sig))
+
+		bad := &Signature{R: sig.R, S: new(big.Int).Add(sig.S, bigOne)}
+		assert.False(t, Verify(&priv.PublicKey, msg, bad))
+	})
+
+	t.Run("Reject out of range signature", func(t *testing.T) {
+		sig := &Signature{R: big.NewInt(0), S: big.NewInt(1)}
+		assert.False(t, Verify(&priv.PublicKey, msg, sig))
+	})
+}
@@ -0,0 +1,92 @@
+package dsa
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/josh-keller/cryptopals/rsa"
+)
+
+var ErrKeyNotFound = errors.New("dsa: private key not found")
+
+// RecoverX solves s = k^-1 (H(m) + x*r) for x when k is known:
+// x = (s*k - H(m)) / r mod q.
+func RecoverX(params Parameters, h *big.Int, sig *Signature, k *big.Int) (*big.Int, error) {
+	rInv, err := rsa.InvMod(sig.R, params.Q)
+	if err != nil {
+		return nil, err
+	}
+
+	x := new(big.Int).Mul(sig.S, k)
+	x.Sub(x, h)
+	x.Mul(x, rInv)
+	return x.Mod(x, params.Q), nil
+}
+
+// Fingerprint is the SHA-1 of the hex encoding of x, the form the challenge
+// uses to confirm the recovered key.
+func Fingerprint(x *big.Int) string {
+	digest := sha1.Sum([]byte(x.Text(16)))
+	return hex.EncodeToString(digest[:])
+}
+
+// BruteForceNonce tries every k in [0, maxK] and returns the private key
+// whose fingerprint matches. The range is split between one goroutine per
+// CPU, and the search stops early if ctx is cancelled.
+func BruteForceNonce(parent context.Context, params Parameters, h *big.Int, sig *Signature, maxK uint64, fingerprint string) (*big.Int, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	workers := uint64(runtime.NumCPU())
+	found := make(chan *big.Int, 1)
+	wg := sync.WaitGroup{}
+
+	for w := uint64(0); w < workers; w++ {
+		wg.Add(1)
+		go func(start uint64) {
+			defer wg.Done()
+			k := new(big.Int)
+			for i := start; i <= maxK; i += workers {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				k.SetUint64(i)
+				x, err := RecoverX(params, h, sig, k)
+				if err != nil {
+					return
+				}
+				if Fingerprint(x) == fingerprint {
+					select {
+					case found <- x:
+					default:
+					}
+					cancel()
+					return
+				}
+				// Avoid wrapping around when maxK is close to the max uint64
+				if maxK-i < workers {
+					return
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	select {
+	case x := <-found:
+		return x, nil
+	default:
+	}
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+	return nil, ErrKeyNotFound
+}
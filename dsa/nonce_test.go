@@ -0,0 +1,72 @@
+package dsa
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const challenge43Msg = "For those that envy a MC it can be hazardous to your health\n" +
+	"So be friendly, a matter of life and death, just like a etch-a-sketch\n"
+
+func challenge43Key() *PublicKey {
+	return &PublicKey{
+		Parameters: ChallengeParameters,
+		Y: mustHex("84ad4719d044495496a3201c8ff484feb45b962e7302e56a392aee4" +
+			"abab3e4bdebf2955b4736012f21a08084056b19bcd7fee56048e004" +
+			"e44984e2f411788efdc837a0d2e5abb7b555039fd243ac01f0fb2ed" +
+			"1dec568280ce678e931868d23eb095fde9d3779191b8c0299d6e07b" +
+			"bb283e6633451e535c45513b2d33c99ea17"),
+	}
+}
+
+func challenge43Sig() *Signature {
+	r, _ := new(big.Int).SetString("548099063082341131477253921760299949438196259240", 10)
+	s, _ := new(big.Int).SetString("857042759984254168557880549501802188789837994940", 10)
+	return &Signature{R: r, S: s}
+}
+
+func TestRecoverX(t *testing.T) {
+	priv, err := GenerateKey(ChallengeParameters)
+	require.NoError(t, err)
+	h := HashMessage([]byte("known nonce"))
+	k := big.NewInt(12345)
+	sig, err := SignWithNonce(priv, h, k)
+	require.NoError(t, err)
+
+	x, err := RecoverX(ChallengeParameters, h, sig, k)
+	require.NoError(t, err)
+	assert.Equal(t, priv.X, x)
+}
+
+func TestBruteForceNonce(t *testing.T) {
+	pub := challenge43Key()
+	sig := challenge43Sig()
+	h := HashMessage([]byte(challenge43Msg))
+
+	t.Run("Message hash matches challenge", func(t *testing.T) {
+		assert.Equal(t, "d2d0714f014a9784047eaeccf956520045c45265", h.Text(16))
+		assert.True(t, VerifyHash(pub, h, sig))
+	})
+
+	t.Run("Recover challenge 43 key", func(t *testing.T) {
+		x, err := BruteForceNonce(context.Background(), ChallengeParameters, h, sig, 1<<16, "0954edd5e0afe5542a4adf012611a91912a3ec16")
+		require.NoError(t, err)
+		assert.Equal(t, pub.Y, new(big.Int).Exp(pub.G, x, pub.P))
+	})
+
+	t.Run("Key not in range", func(t *testing.T) {
+		_, err := BruteForceNonce(context.Background(), ChallengeParameters, h, sig, 100, "0954edd5e0afe5542a4adf012611a91912a3ec16")
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+	})
+
+	t.Run("Cancelled search", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := BruteForceNonce(ctx, ChallengeParameters, h, sig, 1<<16, "0954edd5e0afe5542a4adf012611a91912a3ec16")
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
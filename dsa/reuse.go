@@ -0,0 +1,163 @@
+package dsa
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/josh-keller/cryptopals/rsa"
+)
+
+// SignedMessage is one msg/s/r/m block from a signature log. M is the hash
+// as recorded in the log.
+type SignedMessage struct {
+	Msg string
+	Sig Signature
+	M   *big.Int
+}
+
+// NonceReuse records two messages that were signed with the same k and the
+// values that fall out of that.
+type NonceReuse struct {
+	I, J int
+	K    *big.Int
+	X    *big.Int
+}
+
+// ParseSignedMessages reads the challenge 44 format: blocks of four lines
+// prefixed with "msg: ", "s: ", "r: " and "m: ". s and r are decimal and m
+// is hex.
+func ParseSignedMessages(r io.Reader) ([]SignedMessage, error) {
+	scanner := bufio.NewScanner(r)
+	msgs := []SignedMessage{}
+	current := SignedMessage{}
+	field := 0
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" && field == 0 {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("dsa: line %d: missing field separator", lineNum)
+		}
+
+		expected := []string{"msg", "s", "r", "m"}[field]
+		if key != expected {
+			return nil, fmt.Errorf("dsa: line %d: expected %q, got %q", lineNum, expected, key)
+		}
+
+		var valid bool
+		switch key {
+		case "msg":
+			current.Msg = value
+		case "s":
+			current.Sig.S, valid = new(big.Int).SetString(strings.TrimSpace(value), 10)
+		case "r":
+			current.Sig.R, valid = new(big.Int).SetString(strings.TrimSpace(value), 10)
+		case "m":
+			current.M, valid = new(big.Int).SetString(strings.TrimSpace(value), 16)
+		}
+		if key != "msg" && !valid {
+			return nil, fmt.Errorf("dsa: line %d: invalid number %q", lineNum, value)
+		}
+
+		field++
+		if field == 4 {
+			msgs = append(msgs, current)
+			current = SignedMessage{}
+			field = 0
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if field != 0 {
+		return nil, errors.New("dsa: incomplete message block at end of input")
+	}
+
+	return msgs, nil
+}
+
+func ReadSignedMessagesFile(filename string) ([]SignedMessage, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseSignedMessages(file)
+}
+
+// RecoverNonce computes the shared k from two signatures with the same r:
+// k = (m1 - m2) / (s1 - s2) mod q.
+func RecoverNonce(params Parameters, a, b SignedMessage) (*big.Int, error) {
+	q := params.Q
+	ds := new(big.Int).Sub(a.Sig.S, b.Sig.S)
+	ds.Mod(ds, q)
+	dsInv, err := rsa.InvMod(ds, q)
+	if err != nil {
+		return nil, err
+	}
+
+	k := new(big.Int).Sub(a.M, b.M)
+	k.Mul(k, dsInv)
+	return k.Mod(k, q), nil
+}
+
+// FindReusedNonces groups the messages by r and reports every pair in a
+// group along with the k and x derived from it.
+func FindReusedNonces(params Parameters, msgs []SignedMessage) []NonceReuse {
+	groups := make(map[string][]int)
+	order := []string{}
+	for i, m := range msgs {
+		key := m.Sig.R.String()
+		if _, exists := groups[key]; !exists {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], i)
+	}
+
+	reuses := []NonceReuse{}
+	for _, key := range order {
+		idxs := groups[key]
+		for a := 0; a < len(idxs); a++ {
+			for b := a + 1; b < len(idxs); b++ {
+				i, j := idxs[a], idxs[b]
+				k, err := RecoverNonce(params, msgs[i], msgs[j])
+				if err != nil {
+					// Same s as well, the pair tells us nothing
+					continue
+				}
+				x, err := RecoverX(params, msgs[i].M, &msgs[i].Sig, k)
+				if err != nil {
+					continue
+				}
+				reuses = append(reuses, NonceReuse{I: i, J: j, K: k, X: x})
+			}
+		}
+	}
+
+	return reuses
+}
+
+// RecoverKeyFromReusedNonces returns the first private key derived from a
+// reused nonce that matches pub, along with every leaking pair found.
+func RecoverKeyFromReusedNonces(pub *PublicKey, msgs []SignedMessage) (*big.Int, []NonceReuse, error) {
+	reuses := FindReusedNonces(pub.Parameters, msgs)
+	for _, r := range reuses {
+		if new(big.Int).Exp(pub.G, r.X, pub.P).Cmp(pub.Y) == 0 {
+			return r.X, reuses, nil
+		}
+	}
+
+	return nil, reuses, ErrKeyNotFound
+}
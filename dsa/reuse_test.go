@@ -0,0 +1,112 @@
+package dsa
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSignedMessages(t *testing.T) {
+	t.Run("Parse block", func(t *testing.T) {
+		input := "msg: Listen for me, you better listen for me now. \n" +
+			"s: 1267396447369736888040262262183731677867615804316\n" +
+			"r: 1105520928110492191417703162650245113664610474875\n" +
+			"m: a4db3de27e2db3e5ef085ced2bced91b82e0df19\n"
+		msgs, err := ParseSignedMessages(strings.NewReader(input))
+		require.NoError(t, err)
+		require.Len(t, msgs, 1)
+		assert.Equal(t, "Listen for me, you better listen for me now. ", msgs[0].Msg)
+		assert.Equal(t, "1267396447369736888040262262183731677867615804316", msgs[0].Sig.S.String())
+		assert.Equal(t, "1105520928110492191417703162650245113664610474875", msgs[0].Sig.R.String())
+		assert.Equal(t, "a4db3de27e2db3e5ef085ced2bced91b82e0df19", msgs[0].M.Text(16))
+	})
+
+	t.Run("Reject out of order fields", func(t *testing.T) {
+		_, err := ParseSignedMessages(strings.NewReader("msg: hi\nr: 1\ns: 2\nm: 3\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("Reject truncated block", func(t *testing.T) {
+		_, err := ParseSignedMessages(strings.NewReader("msg: hi\ns: 1\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestFindReusedNonces(t *testing.T) {
+	priv, err := GenerateKey(ChallengeParameters)
+	require.NoError(t, err)
+
+	// Write a log where messages 1 and 4 share a nonce
+	reused := big.NewInt(987654321)
+	builder := strings.Builder{}
+	for i := 0; i < 6; i++ {
+		msg := fmt.Sprintf("Message number %d", i)
+		h := HashMessage([]byte(msg))
+		var sig *Signature
+		if i == 1 || i == 4 {
+			sig, err = SignWithNonce(priv, h, reused)
+		} else {
+			sig, err = Sign(priv, []byte(msg))
+		}
+		require.NoError(t, err)
+		fmt.Fprintf(&builder, "msg: %s\ns: %s\nr: %s\nm: %s\n", msg, sig.S, sig.R, h.Text(16))
+	}
+	filename := filepath.Join(t.TempDir(), "44.txt")
+	require.NoError(t, os.WriteFile(filename, []byte(builder.String()), 0o600))
+
+	msgs, err := ReadSignedMessagesFile(filename)
+	require.NoError(t, err)
+	require.Len(t, msgs, 6)
+
+	t.Run("Detect the leaking pair", func(t *testing.T) {
+		reuses := FindReusedNonces(ChallengeParameters, msgs)
+		require.Len(t, reuses, 1)
+		assert.Equal(t, 1, reuses[0].I)
+		assert.Equal(t, 4, reuses[0].J)
+		assert.Equal(t, reused, reuses[0].K)
+	})
+
+	t.Run("Recover private key", func(t *testing.T) {
+		x, reuses, err := RecoverKeyFromReusedNonces(&priv.PublicKey, msgs)
+		require.NoError(t, err)
+		assert.Len(t, reuses, 1)
+		assert.Equal(t, priv.X, x)
+	})
+
+	t.Run("No reuse means no key", func(t *testing.T) {
+		_, reuses, err := RecoverKeyFromReusedNonces(&priv.PublicKey, msgs[:3])
+		assert.ErrorIs(t, err, ErrKeyNotFound)
+		assert.Empty(t, reuses)
+	})
+}
+
+func TestChallenge44(t *testing.T) {
+	msgs, err := ReadSignedMessagesFile("../inputs/44.txt")
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Skip("inputs/44.txt is not checked out")
+	}
+	require.NoError(t, err)
+	require.NotEmpty(t, msgs)
+
+	pub := &PublicKey{
+		Parameters: ChallengeParameters,
+		Y:          mustHex("2d026f4bf30195ede3a088da85e398ef869611d0f68f0713d51c9c1a3a26c95105d915e2d8cdf26d056b86b8a7b85519b1c23cc3ecdc6062650462e3063bd179c2a6581519f674a61f1d89a1fff27171ebc1b93d4dc57bceb7ae2430f98a6a4d83d8279ee65d71c1203d2c96d65ebbf7cce9d32971c3de5084cce04a2e147821"),
+	}
+	for i, msg := range msgs {
+		assert.Equal(t, HashMessage([]byte(msg.Msg)), msg.M, "message %d", i)
+		assert.True(t, VerifyHash(pub, msg.M, &msg.Sig), "message %d", i)
+	}
+
+	x, reuses, err := RecoverKeyFromReusedNonces(pub, msgs)
+	require.NoError(t, err)
+	assert.NotEmpty(t, reuses)
+	assert.Equal(t, "ca8f6f7c66fa362d40760d135b763eb8527d3d52", Fingerprint(x))
+}
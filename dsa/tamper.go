@@ -0,0 +1,75 @@
+package dsa
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/josh-keller/cryptopals/rsa"
+)
+
+type VerifyOptions struct {
+	// Hardened rejects degenerate domain parameters and signatures with r or
+	// s outside (0, q). Without it the verifier trusts whatever it is given.
+	Hardened bool
+}
+
+// ValidateParameters checks that g generates a subgroup of order q in Z_p*.
+// This rules out substitutions like g = 0 or g = p+1.
+func ValidateParameters(params Parameters) error {
+	p, q, g := params.P, params.Q, params.G
+	if p == nil || q == nil || g == nil {
+		return errors.New("dsa: missing parameters")
+	}
+	if p.Cmp(bigOne) <= 0 || q.Cmp(bigOne) <= 0 {
+		return errors.New("dsa: p and q must be greater than 1")
+	}
+	if new(big.Int).Mod(new(big.Int).Sub(p, bigOne), q).Sign() != 0 {
+		return errors.New("dsa: q does not divide p-1")
+	}
+	if g.Cmp(bigOne) <= 0 || g.Cmp(p) >= 0 {
+		return errors.New("dsa: g must be in (1, p)")
+	}
+	if new(big.Int).Exp(g, q, p).Cmp(bigOne) != 0 {
+		return errors.New("dsa: g does not have order q")
+	}
+
+	return nil
+}
+
+// VerifyWithParameters verifies sig against y using the caller's choice of
+// domain parameters.
+func VerifyWithParameters(params Parameters, y *big.Int, msg []byte, sig *Signature, opts VerifyOptions) bool {
+	pub := &PublicKey{Parameters: params, Y: y}
+	if opts.Hardened {
+		if ValidateParameters(params) != nil {
+			return false
+		}
+		return VerifyHash(pub, HashMessage(msg), sig)
+	}
+
+	return verify(pub, HashMessage(msg), sig)
+}
+
+// ForgeZeroGenerator returns a signature that an unchecked verifier accepts
+// for any message when g = 0: g^u1 is 0, so v is 0 and r = 0 matches.
+func ForgeZeroGenerator() *Signature {
+	return &Signature{R: big.NewInt(0), S: big.NewInt(1)}
+}
+
+// ForgeOneGenerator returns a signature that an unchecked verifier accepts
+// for any message when g = p+1 (so g = 1 mod p). Picking r = y^z mod p mod q
+// and s = r/z makes v = y^(r/s) = y^z, independent of the message.
+func ForgeOneGenerator(params Parameters, y, z *big.Int) (*Signature, error) {
+	q := params.Q
+	r := new(big.Int).Exp(y, z, params.P)
+	r.Mod(r, q)
+
+	zInv, err := rsa.InvMod(z, q)
+	if err != nil {
+		return nil, err
+	}
+	s := new(big.Int).Mul(r, zInv)
+	s.Mod(s, q)
+
+	return &Signature{R: r, S: s}, nil
+}
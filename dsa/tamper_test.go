@@ -0,0 +1,51 @@
+package dsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameterTampering(t *testing.T) {
+	priv, err := GenerateKey(ChallengeParameters)
+	require.NoError(t, err)
+	y := priv.Y
+	messages := [][]byte{[]byte("Hello, world"), []byte("Goodbye, world")}
+	unchecked := VerifyOptions{}
+	hardened := VerifyOptions{Hardened: true}
+
+	t.Run("Challenge parameters are valid", func(t *testing.T) {
+		assert.NoError(t, ValidateParameters(ChallengeParameters))
+		sig, err := Sign(priv, messages[0])
+		require.NoError(t, err)
+		assert.True(t, VerifyWithParameters(ChallengeParameters, y, messages[0], sig, unchecked))
+		assert.True(t, VerifyWithParameters(ChallengeParameters, y, messages[0], sig, hardened))
+	})
+
+	t.Run("g = 0", func(t *testing.T) {
+		params := ChallengeParameters
+		params.G = big.NewInt(0)
+		assert.Error(t, ValidateParameters(params))
+
+		sig := ForgeZeroGenerator()
+		for _, msg := range messages {
+			assert.True(t, VerifyWithParameters(params, y, msg, sig, unchecked))
+			assert.False(t, VerifyWithParameters(params, y, msg, sig, hardened))
+		}
+	})
+
+	t.Run("g = p + 1", func(t *testing.T) {
+		params := ChallengeParameters
+		params.G = new(big.Int).Add(params.P, bigOne)
+		assert.Error(t, ValidateParameters(params))
+
+		sig, err := ForgeOneGenerator(params, y, big.NewInt(7))
+		require.NoError(t, err)
+		for _, msg := range messages {
+			assert.True(t, VerifyWithParameters(params, y, msg, sig, unchecked))
+			assert.False(t, VerifyWithParameters(params, y, msg, sig, hardened))
+		}
+	})
+}
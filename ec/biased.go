@@ -0,0 +1,77 @@
+package ec
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/josh-keller/cryptopals/lattice"
+	"github.com/josh-keller/cryptopals/rsa"
+)
+
+type SignedMessage struct {
+	Msg []byte
+	Sig *Signature
+}
+
+// SignBiased signs with a nonce whose low bits are zero, the kind of flaw
+// that comes from a broken RNG or a sloppy truncation.
+func SignBiased(priv *PrivateKey, msg []byte, bits uint) (*Signature, error) {
+	for {
+		k, err := rand.Int(rand.Reader, priv.N)
+		if err != nil {
+			return nil, err
+		}
+		k.Rsh(k, bits)
+		k.Lsh(k, bits)
+
+		sig, err := SignWithNonce(priv, msg, k)
+		if errors.Is(err, errBadNonce) {
+			continue
+		}
+		return sig, err
+	}
+}
+
+// BiasedNonceAttack recovers the private key from signatures whose nonces
+// have their low l bits zeroed. From s = (e + r*d)/k and k = 2^l * b,
+//
+//	b = d * r/(s*2^l) + e/(s*2^l) (mod q)
+//
+// with 0 <= b < q/2^l. Subtracting q/2^(l+1) centres b on zero, which turns
+// every signature into a hidden number problem sample leaking l bits of d.
+// A few more samples than q's bits divided by l are usually enough.
+func BiasedNonceAttack(params *Params, pub *Point, signed []SignedMessage, l uint) (*big.Int, error) {
+	q := params.N
+	centre := new(big.Int).Rsh(q, l+1)
+	h := &lattice.HNP{Q: q, L: l}
+
+	for _, sm := range signed {
+		denom := new(big.Int).Lsh(sm.Sig.S, l)
+		inv, err := rsa.InvMod(denom, q)
+		if err != nil {
+			return nil, err
+		}
+
+		t := new(big.Int).Mul(sm.Sig.R, inv)
+		t.Mod(t, q)
+		u := new(big.Int).Mul(hashToInt(sm.Msg, q), inv)
+		u.Sub(u, centre)
+		u.Mod(u, q)
+
+		h.T = append(h.T, t)
+		h.U = append(h.U, u)
+	}
+
+	candidates, err := h.Solve()
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range candidates {
+		if params.ScalarMult(params.G, d).Equal(pub) {
+			return d, nil
+		}
+	}
+
+	return nil, errors.New("ec: lattice did not reveal the key, try more signatures")
+}
@@ -0,0 +1,53 @@
+package ec
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignBiased(t *testing.T) {
+	priv, err := GenerateKey(&Challenge59Params)
+	require.NoError(t, err)
+
+	sig, err := SignBiased(priv, []byte("hi mom"), 8)
+	require.NoError(t, err)
+	assert.True(t, Verify(priv.Params, priv.Pub, []byte("hi mom"), sig))
+}
+
+func TestBiasedNonceAttack(t *testing.T) {
+	priv, err := GenerateKey(&Challenge59Params)
+	require.NoError(t, err)
+
+	signed := make([]SignedMessage, 22)
+	for i := range signed {
+		msg := []byte(fmt.Sprintf("message %d", i))
+		sig, err := SignBiased(priv, msg, 8)
+		require.NoError(t, err)
+		signed[i] = SignedMessage{Msg: msg, Sig: sig}
+	}
+
+	t.Run("Enough signatures", func(t *testing.T) {
+		d, err := BiasedNonceAttack(priv.Params, priv.Pub, signed, 8)
+		require.NoError(t, err)
+		assert.Equal(t, priv.D, d)
+	})
+
+	t.Run("Too few signatures", func(t *testing.T) {
+		_, err := BiasedNonceAttack(priv.Params, priv.Pub, signed[:8], 8)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unbiased nonces", func(t *testing.T) {
+		unbiased := make([]SignedMessage, len(signed))
+		for i, sm := range signed {
+			sig, err := Sign(priv, sm.Msg)
+			require.NoError(t, err)
+			unbiased[i] = SignedMessage{Msg: sm.Msg, Sig: sig}
+		}
+		_, err := BiasedNonceAttack(priv.Params, priv.Pub, unbiased, 8)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,154 @@
+package ec
+
+import "math/big"
+
+var (
+	bigOne   = big.NewInt(1)
+	bigTwo   = big.NewInt(2)
+	bigThree = big.NewInt(3)
+)
+
+// Curve is y^2 = x^3 + ax + b over GF(p).
+type Curve struct {
+	A, B, P *big.Int
+}
+
+// Point is an affine point. The identity (point at infinity) has nil
+// coordinates.
+type Point struct {
+	X, Y *big.Int
+}
+
+func Infinity() *Point {
+	return &Point{}
+}
+
+func NewPoint(x, y int64) *Point {
+	return &Point{X: big.NewInt(x), Y: big.NewInt(y)}
+}
+
+func (pt *Point) IsInfinity() bool {
+	return pt.X == nil
+}
+
+func (pt *Point) Equal(other *Point) bool {
+	if pt.IsInfinity() || other.IsInfinity() {
+		return pt.IsInfinity() == other.IsInfinity()
+	}
+	return pt.X.Cmp(other.X) == 0 && pt.Y.Cmp(other.Y) == 0
+}
+
+func (c *Curve) IsOnCurve(pt *Point) bool {
+	if pt.IsInfinity() {
+		return true
+	}
+	lhs := new(big.Int).Mul(pt.Y, pt.Y)
+	lhs.Mod(lhs, c.P)
+	return lhs.Cmp(c.rhs(pt.X)) == 0
+}
+
+// rhs computes x^3 + ax + b mod p.
+func (c *Curve) rhs(x *big.Int) *big.Int {
+	r := new(big.Int).Mul(x, x)
+	r.Add(r, c.A)
+	r.Mul(r, x)
+	r.Add(r, c.B)
+	return r.Mod(r, c.P)
+}
+
+func (c *Curve) Neg(pt *Point) *Point {
+	if pt.IsInfinity() {
+		return Infinity()
+	}
+	y := new(big.Int).Neg(pt.Y)
+	return &Point{X: new(big.Int).Set(pt.X), Y: y.Mod(y, c.P)}
+}
+
+// reduce brings pt's coordinates into [0, p). Peers can send anything, and
+// x + p would otherwise slip past the equal x check in Add.
+func (c *Curve) reduce(pt *Point) *Point {
+	if pt.IsInfinity() {
+		return pt
+	}
+	inRange := func(v *big.Int) bool {
+		return v.Sign() >= 0 && v.Cmp(c.P) < 0
+	}
+	if inRange(pt.X) && inRange(pt.Y) {
+		return pt
+	}
+	return &Point{X: new(big.Int).Mod(pt.X, c.P), Y: new(big.Int).Mod(pt.Y, c.P)}
+}
+
+// Add is the chord and tangent law. Note that b never appears, which is why
+// points from another curve with a different b still work.
+func (c *Curve) Add(p1, p2 *Point) *Point {
+	if p1.IsInfinity() {
+		return c.reduce(p2)
+	}
+	if p2.IsInfinity() {
+		return c.reduce(p1)
+	}
+	p1, p2 = c.reduce(p1), c.reduce(p2)
+
+	var m *big.Int
+	if p1.X.Cmp(p2.X) == 0 {
+		// Same x means p2 is either p1 or -p1
+		if p1.Y.Cmp(p2.Y) != 0 || p1.Y.Sign() == 0 {
+			return Infinity()
+		}
+		// (3x^2 + a) / 2y
+		num := new(big.Int).Mul(p1.X, p1.X)
+		num.Mul(num, bigThree)
+		num.Add(num, c.A)
+		den := new(big.Int).Mul(p1.Y, bigTwo)
+		m = c.div(num, den)
+	} else {
+		num := new(big.Int).Sub(p2.Y, p1.Y)
+		den := new(big.Int).Sub(p2.X, p1.X)
+		m = c.div(num, den)
+	}
+
+	x3 := new(big.Int).Mul(m, m)
+	x3.Sub(x3, p1.X)
+	x3.Sub(x3, p2.X)
+	x3.Mod(x3, c.P)
+
+	y3 := new(big.Int).Sub(p1.X, x3)
+	y3.Mul(y3, m)
+	y3.Sub(y3, p1.Y)
+	y3.Mod(y3, c.P)
+
+	return &Point{X: x3, Y: y3}
+}
+
+func (c *Curve) Double(pt *Point) *Point {
+	return c.Add(pt, pt)
+}
+
+func (c *Curve) div(num, den *big.Int) *big.Int {
+	den = new(big.Int).Mod(den, c.P)
+	// math/big's inverse is an order of magnitude faster than rsa.InvMod,
+	// which matters once the kangaroo is doing millions of additions
+	inv := new(big.Int).ModInverse(den, c.P)
+	if inv == nil {
+		panic("Division by zero on curve")
+	}
+	r := inv.Mul(inv, num)
+	return r.Mod(r, c.P)
+}
+
+// ScalarMult computes k*pt by double and add.
+func (c *Curve) ScalarMult(pt *Point, k *big.Int) *Point {
+	if k.Sign() < 0 {
+		return c.ScalarMult(c.Neg(pt), new(big.Int).Neg(k))
+	}
+
+	result := Infinity()
+	for i := k.BitLen() - 1; i >= 0; i-- {
+		result = c.Double(result)
+		if k.Bit(i) == 1 {
+			result = c.Add(result, pt)
+		}
+	}
+	return result
+}
@@ -0,0 +1,44 @@
+package ec
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurveArithmetic(t *testing.T) {
+	// y^2 = x^3 + 2x + 3 over GF(97)
+	c := &Curve{A: big.NewInt(2), B: big.NewInt(3), P: big.NewInt(97)}
+	p := NewPoint(3, 6)
+	assert.True(t, c.IsOnCurve(p))
+
+	t.Run("Identity", func(t *testing.T) {
+		assert.True(t, c.Add(p, Infinity()).Equal(p))
+		assert.True(t, c.Add(Infinity(), p).Equal(p))
+		assert.True(t, c.Add(p, c.Neg(p)).IsInfinity())
+	})
+
+	t.Run("Double and add", func(t *testing.T) {
+		assert.True(t, c.Double(p).Equal(NewPoint(80, 10)))
+		assert.True(t, c.Add(c.Double(p), p).Equal(NewPoint(80, 87)))
+		assert.True(t, c.IsOnCurve(c.Double(p)))
+	})
+
+	t.Run("Scalar multiplication", func(t *testing.T) {
+		sum := Infinity()
+		for k := int64(0); k < 10; k++ {
+			assert.True(t, c.ScalarMult(p, big.NewInt(k)).Equal(sum), "k = %d", k)
+			sum = c.Add(sum, p)
+		}
+		// (3, 6) has order 5
+		assert.True(t, c.ScalarMult(p, big.NewInt(5)).IsInfinity())
+		assert.True(t, c.ScalarMult(p, big.NewInt(-1)).Equal(c.Neg(p)))
+	})
+}
+
+func TestChallenge59Params(t *testing.T) {
+	params := Challenge59Params
+	assert.True(t, params.IsOnCurve(params.G))
+	assert.True(t, params.ScalarMult(params.G, params.N).IsInfinity())
+}
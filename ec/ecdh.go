@@ -0,0 +1,89 @@
+package ec
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+)
+
+// Params is a curve with a base point G of prime order N.
+type Params struct {
+	Curve
+	G *Point
+	N *big.Int
+}
+
+func mustDecimal(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("Invalid decimal constant")
+	}
+	return n
+}
+
+// Challenge59Params is y^2 = x^3 - 95051x + 11279326 from challenge 59. The
+// whole curve has order 233970423115425145498902418297807005944.
+var Challenge59Params = Params{
+	Curve: Curve{
+		A: big.NewInt(-95051),
+		B: big.NewInt(11279326),
+		P: mustDecimal("233970423115425145524320034830162017933"),
+	},
+	G: &Point{
+		X: big.NewInt(182),
+		Y: mustDecimal("85518893674295321206118380980485522083"),
+	},
+	N: mustDecimal("29246302889428143187362802287225875743"),
+}
+
+type PrivateKey struct {
+	*Params
+	D   *big.Int
+	Pub *Point
+}
+
+func GenerateKey(params *Params) (*PrivateKey, error) {
+	d, err := rand.Int(rand.Reader, new(big.Int).Sub(params.N, bigOne))
+	if err != nil {
+		return nil, err
+	}
+	d.Add(d, bigOne)
+
+	return &PrivateKey{
+		Params: params,
+		D:      d,
+		Pub:    params.ScalarMult(params.G, d),
+	}, nil
+}
+
+// SharedSecret multiplies the peer's point by d. It doesn't check the point
+// is on the curve.
+func (priv *PrivateKey) SharedSecret(peer *Point) *Point {
+	return priv.ScalarMult(peer, priv.D)
+}
+
+// MAC is HMAC-SHA256 keyed with SHA-256 of both coordinates of the shared
+// point.
+func MAC(secret *Point, msg []byte) []byte {
+	h := sha256.New()
+	if !secret.IsInfinity() {
+		h.Write(secret.X.Bytes())
+		h.Write([]byte{0})
+		h.Write(secret.Y.Bytes())
+	}
+	mac := hmac.New(sha256.New, h.Sum(nil))
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
+
+// MACOracle takes the attacker's public point and returns a message and its
+// MAC under the shared secret.
+type MACOracle func(pt *Point) ([]byte, []byte)
+
+func NewBob(priv *PrivateKey) MACOracle {
+	msg := []byte("crazy flamboyant for the rap enjoyment")
+	return func(pt *Point) ([]byte, []byte) {
+		return msg, MAC(priv.SharedSecret(pt), msg)
+	}
+}
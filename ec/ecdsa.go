@@ -0,0 +1,144 @@
+package ec
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/josh-keller/cryptopals/rsa"
+)
+
+type Signature struct {
+	R, S *big.Int
+}
+
+// hashToInt is SHA-256 of msg truncated to the bit length of n, as in FIPS
+// 186.
+func hashToInt(msg []byte, n *big.Int) *big.Int {
+	h := sha256.Sum256(msg)
+	e := new(big.Int).SetBytes(h[:])
+	if excess := len(h)*8 - n.BitLen(); excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	return e
+}
+
+func Sign(priv *PrivateKey, msg []byte) (*Signature, error) {
+	for {
+		k, err := rand.Int(rand.Reader, new(big.Int).Sub(priv.N, bigOne))
+		if err != nil {
+			return nil, err
+		}
+		k.Add(k, bigOne)
+
+		sig, err := SignWithNonce(priv, msg, k)
+		if errors.Is(err, errBadNonce) {
+			continue
+		}
+		return sig, err
+	}
+}
+
+var errBadNonce = errors.New("ec: nonce gives a zero signature component")
+
+// SignWithNonce signs with a caller supplied k, which must never be reused
+// or leak.
+func SignWithNonce(priv *PrivateKey, msg []byte, k *big.Int) (*Signature, error) {
+	r := priv.ScalarMult(priv.G, k).X
+	if r == nil {
+		return nil, errBadNonce
+	}
+	r = new(big.Int).Mod(r, priv.N)
+	if r.Sign() == 0 {
+		return nil, errBadNonce
+	}
+
+	kInv, err := rsa.InvMod(k, priv.N)
+	if err != nil {
+		return nil, errBadNonce
+	}
+	s := new(big.Int).Mul(r, priv.D)
+	s.Add(s, hashToInt(msg, priv.N))
+	s.Mul(s, kInv)
+	s.Mod(s, priv.N)
+	if s.Sign() == 0 {
+		return nil, errBadNonce
+	}
+
+	return &Signature{R: r, S: s}, nil
+}
+
+// verifyPoint returns u1*G + u2*Q, whose x coordinate should be r, or nil if
+// the signature is out of range.
+func verifyPoint(params *Params, pub *Point, msg []byte, sig *Signature) *Point {
+	if sig.R.Sign() <= 0 || sig.R.Cmp(params.N) >= 0 {
+		return nil
+	}
+	if sig.S.Sign() <= 0 || sig.S.Cmp(params.N) >= 0 {
+		return nil
+	}
+
+	w, err := rsa.InvMod(sig.S, params.N)
+	if err != nil {
+		return nil
+	}
+	u1 := new(big.Int).Mul(hashToInt(msg, params.N), w)
+	u1.Mod(u1, params.N)
+	u2 := new(big.Int).Mul(sig.R, w)
+	u2.Mod(u2, params.N)
+
+	return params.Add(params.ScalarMult(params.G, u1), params.ScalarMult(pub, u2))
+}
+
+func Verify(params *Params, pub *Point, msg []byte, sig *Signature) bool {
+	pt := verifyPoint(params, pub, msg, sig)
+	if pt == nil || pt.IsInfinity() {
+		return false
+	}
+	return new(big.Int).Mod(pt.X, params.N).Cmp(sig.R) == 0
+}
+
+// DuplicateSignatureKey builds new domain parameters and a key pair under
+// which sig, a valid signature on msg, also verifies for target. Verification
+// lands on R = u1*G + u2*Q. Picking any d' and setting
+// G' = (u1' + u2*d')^-1 * R and Q' = d'*G' lands on the same R with target's
+// u1'. Nothing in ECDSA ties the signer to the generator, so a verifier that
+// lets the key choose it accepts.
+func DuplicateSignatureKey(params *Params, pub *Point, msg []byte, sig *Signature, target []byte) (*PrivateKey, error) {
+	if !Verify(params, pub, msg, sig) {
+		return nil, errors.New("ec: signature does not verify")
+	}
+	r := verifyPoint(params, pub, msg, sig)
+	n := params.N
+
+	w, err := rsa.InvMod(sig.S, n)
+	if err != nil {
+		return nil, err
+	}
+	u1 := new(big.Int).Mul(hashToInt(target, n), w)
+	u2 := new(big.Int).Mul(sig.R, w)
+
+	for {
+		d, err := rand.Int(rand.Reader, new(big.Int).Sub(n, bigOne))
+		if err != nil {
+			return nil, err
+		}
+		d.Add(d, bigOne)
+
+		t := new(big.Int).Mul(u2, d)
+		t.Add(t, u1)
+		tInv, err := rsa.InvMod(t, n)
+		if err != nil {
+			continue
+		}
+
+		g := params.ScalarMult(r, tInv)
+		forged := &Params{Curve: params.Curve, G: g, N: n}
+		return &PrivateKey{
+			Params: forged,
+			D:      d,
+			Pub:    forged.ScalarMult(g, d),
+		}, nil
+	}
+}
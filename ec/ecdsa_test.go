@@ -0,0 +1,50 @@
+package ec
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECDSA(t *testing.T) {
+	priv, err := GenerateKey(&Challenge59Params)
+	require.NoError(t, err)
+	msg := []byte("hi mom")
+
+	sig, err := Sign(priv, msg)
+	require.NoError(t, err)
+	assert.True(t, Verify(priv.Params, priv.Pub, msg, sig))
+	assert.False(t, Verify(priv.Params, priv.Pub, []byte("hi dad"), sig))
+
+	bad := &Signature{R: sig.R, S: new(big.Int).Add(sig.S, bigOne)}
+	assert.False(t, Verify(priv.Params, priv.Pub, msg, bad))
+	assert.False(t, Verify(priv.Params, priv.Pub, msg, &Signature{R: new(big.Int), S: sig.S}))
+}
+
+func TestDuplicateSignatureKey(t *testing.T) {
+	priv, err := GenerateKey(&Challenge59Params)
+	require.NoError(t, err)
+	msg := []byte("Transfer $10 to Bob")
+	sig, err := Sign(priv, msg)
+	require.NoError(t, err)
+
+	for _, target := range [][]byte{msg, []byte("Transfer $1000000 to Eve")} {
+		forged, err := DuplicateSignatureKey(priv.Params, priv.Pub, msg, sig, target)
+		require.NoError(t, err)
+
+		assert.True(t, forged.IsOnCurve(forged.G))
+		assert.True(t, forged.ScalarMult(forged.G, forged.N).IsInfinity())
+		assert.True(t, Verify(forged.Params, forged.Pub, target, sig))
+
+		if string(target) != string(msg) {
+			assert.False(t, Verify(priv.Params, priv.Pub, target, sig))
+		}
+
+		// The forged key is a real key pair, it can sign new messages too
+		sig2, err := Sign(forged, []byte("another"))
+		require.NoError(t, err)
+		assert.True(t, Verify(forged.Params, forged.Pub, []byte("another"), sig2))
+	}
+}
@@ -0,0 +1,131 @@
+package ec
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/josh-keller/cryptopals/dh"
+	"github.com/josh-keller/cryptopals/rsa"
+)
+
+// InvalidCurve is a curve sharing a and p with the target but with another
+// b, along with its order.
+type InvalidCurve struct {
+	B     *big.Int
+	Order *big.Int
+}
+
+// Challenge59InvalidCurves all have orders with plenty of small factors.
+var Challenge59InvalidCurves = []InvalidCurve{
+	{B: big.NewInt(210), Order: mustDecimal("233970423115425145550826547352470124412")},
+	{B: big.NewInt(504), Order: mustDecimal("233970423115425145544350131142039591210")},
+	{B: big.NewInt(727), Order: mustDecimal("233970423115425145545378039958152057148")},
+}
+
+// RandomPoint picks random x until x^3 + ax + b is a square.
+func (c *Curve) RandomPoint() (*Point, error) {
+	for {
+		x, err := rand.Int(rand.Reader, c.P)
+		if err != nil {
+			return nil, err
+		}
+		rhs := c.rhs(x)
+		if rhs.Sign() == 0 {
+			return &Point{X: x, Y: new(big.Int)}, nil
+		}
+		if big.Jacobi(rhs, c.P) != 1 {
+			continue
+		}
+		return &Point{X: x, Y: new(big.Int).ModSqrt(rhs, c.P)}, nil
+	}
+}
+
+// PointOfOrder finds a point of prime order r on a curve of the given order.
+// The r part of the group isn't always cyclic, so rather than multiplying by
+// order/r, strip every factor of r from the order and then multiply by r
+// until the next step would reach the identity.
+func (c *Curve) PointOfOrder(order *big.Int, r uint64) (*Point, error) {
+	bigR := new(big.Int).SetUint64(r)
+	cofactor, rem := new(big.Int).DivMod(order, bigR, new(big.Int))
+	if rem.Sign() != 0 {
+		return nil, errors.New("ec: r does not divide the curve order")
+	}
+	for new(big.Int).Mod(cofactor, bigR).Sign() == 0 {
+		cofactor.Div(cofactor, bigR)
+	}
+
+	for {
+		pt, err := c.RandomPoint()
+		if err != nil {
+			return nil, err
+		}
+		h := c.ScalarMult(pt, cofactor)
+		if h.IsInfinity() {
+			continue
+		}
+		for {
+			next := c.ScalarMult(h, bigR)
+			if next.IsInfinity() {
+				return h, nil
+			}
+			h = next
+		}
+	}
+}
+
+// InvalidCurveAttack recovers Bob's private key by sending him points of
+// small order from curves that differ only in b. His scalar multiplication
+// never uses b, so the shared secret lands in the small subgroup, and the
+// MAC reveals d mod r. The residues from every curve are combined with the
+// CRT once their product exceeds the order of the real base point.
+func InvalidCurveAttack(params *Params, curves []InvalidCurve, bob MACOracle, bound uint64) (*big.Int, error) {
+	residues := []*big.Int{}
+	moduli := []*big.Int{}
+	product := big.NewInt(1)
+	used := make(map[uint64]bool)
+
+	for _, ic := range curves {
+		curve := &Curve{A: params.A, B: ic.B, P: params.P}
+		for _, r := range dh.SmallFactors(ic.Order, bound) {
+			if used[r] {
+				continue
+			}
+			used[r] = true
+
+			h, err := curve.PointOfOrder(ic.Order, r)
+			if err != nil {
+				return nil, err
+			}
+			msg, mac := bob(h)
+
+			found := false
+			guess := Infinity()
+			for k := uint64(0); k < r; k++ {
+				if hmac.Equal(MAC(guess, msg), mac) {
+					residues = append(residues, new(big.Int).SetUint64(k))
+					found = true
+					break
+				}
+				guess = curve.Add(guess, h)
+			}
+			if !found {
+				return nil, errors.New("ec: no residue matches MAC")
+			}
+
+			bigR := new(big.Int).SetUint64(r)
+			moduli = append(moduli, bigR)
+			product.Mul(product, bigR)
+			if product.Cmp(params.N) > 0 {
+				d, err := rsa.CRT(residues, moduli)
+				if err != nil {
+					return nil, err
+				}
+				return d.Mod(d, params.N), nil
+			}
+		}
+	}
+
+	return nil, errors.New("ec: small factors don't cover the base point order")
+}
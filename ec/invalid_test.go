@@ -0,0 +1,55 @@
+package ec
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECDH(t *testing.T) {
+	alice, err := GenerateKey(&Challenge59Params)
+	require.NoError(t, err)
+	bob, err := GenerateKey(&Challenge59Params)
+	require.NoError(t, err)
+
+	assert.True(t, alice.SharedSecret(bob.Pub).Equal(bob.SharedSecret(alice.Pub)))
+}
+
+func TestPointOfOrder(t *testing.T) {
+	ic := Challenge59InvalidCurves[0]
+	curve := &Curve{A: Challenge59Params.A, B: ic.B, P: Challenge59Params.P}
+	for _, r := range []uint64{2, 11, 4999} {
+		pt, err := curve.PointOfOrder(ic.Order, r)
+		require.NoError(t, err)
+		assert.True(t, curve.IsOnCurve(pt))
+		assert.False(t, Challenge59Params.IsOnCurve(pt))
+		assert.True(t, curve.ScalarMult(pt, new(big.Int).SetUint64(r)).IsInfinity())
+	}
+}
+
+func TestInvalidCurveAttack(t *testing.T) {
+	priv, err := GenerateKey(&Challenge59Params)
+	require.NoError(t, err)
+
+	d, err := InvalidCurveAttack(&Challenge59Params, Challenge59InvalidCurves, NewBob(priv), 1<<16)
+	require.NoError(t, err)
+	assert.Equal(t, priv.D, d)
+}
+
+func TestUnreducedPeerPoint(t *testing.T) {
+	ic := Challenge59InvalidCurves[0]
+	curve := &Curve{A: Challenge59Params.A, B: ic.B, P: Challenge59Params.P}
+	pt, err := curve.PointOfOrder(ic.Order, 11)
+	require.NoError(t, err)
+	shifted := &Point{X: new(big.Int).Add(pt.X, curve.P), Y: new(big.Int).Sub(pt.Y, curve.P)}
+
+	for i := 0; i < 20; i++ {
+		priv, err := GenerateKey(&Challenge59Params)
+		require.NoError(t, err)
+		var secret *Point
+		require.NotPanics(t, func() { secret = priv.SharedSecret(shifted) })
+		assert.True(t, secret.Equal(priv.SharedSecret(pt)))
+	}
+}
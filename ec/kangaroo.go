@@ -0,0 +1,66 @@
+package ec
+
+import (
+	"math/big"
+
+	"github.com/josh-keller/cryptopals/dh"
+)
+
+// jumpKey is what the jump function sees for pt. The identity has no x, so
+// it gets 0.
+func jumpKey(pt *Point) *big.Int {
+	if pt.IsInfinity() {
+		return new(big.Int)
+	}
+	return pt.X
+}
+
+// Kangaroo finds x in [a, b] with x*g = y. It is the same walk as
+// dh.KangarooWithOptions with point addition in place of multiplication,
+// and jumps are picked from the x coordinate.
+func (c *Curve) Kangaroo(g, y *Point, a, b uint64, opts dh.KangarooOptions) (*big.Int, error) {
+	x, _, err := c.kangaroo(g, []*Point{y}, a, b, opts)
+	return x, err
+}
+
+// kangaroo sets one trap with the tame kangaroo and then sends a wild one
+// from each of ys in turn, returning the log of the first to land in it and
+// its index.
+func (c *Curve) kangaroo(g *Point, ys []*Point, a, b uint64, opts dh.KangarooOptions) (*big.Int, int, error) {
+	plan, err := opts.Plan(a, b)
+	if err != nil {
+		return nil, 0, err
+	}
+	points := make([]*Point, len(plan.Sizes))
+	for i, size := range plan.Sizes {
+		points[i] = c.ScalarMult(g, new(big.Int).SetUint64(size))
+	}
+	k := len(plan.Sizes)
+
+	xT := uint64(0)
+	yT := c.ScalarMult(g, new(big.Int).SetUint64(b))
+	for i := uint64(0); i < plan.TameJumps; i++ {
+		j := plan.Jump(jumpKey(yT), k)
+		xT += plan.Sizes[j]
+		yT = c.Add(yT, points[j])
+	}
+
+	limit := b - a + xT
+	for idx, y := range ys {
+		xW := uint64(0)
+		yW := y
+		for xW < limit {
+			j := plan.Jump(jumpKey(yW), k)
+			xW += plan.Sizes[j]
+			yW = c.Add(yW, points[j])
+
+			if yW.Equal(yT) {
+				x := new(big.Int).SetUint64(b)
+				x.Add(x, new(big.Int).SetUint64(xT))
+				return x.Sub(x, new(big.Int).SetUint64(xW)), idx, nil
+			}
+		}
+	}
+
+	return nil, 0, dh.ErrKangarooEscaped
+}
@@ -0,0 +1,217 @@
+package ec
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+)
+
+// MontgomeryCurve is Bv^2 = u^3 + Au^2 + u over GF(p).
+type MontgomeryCurve struct {
+	A, B, P *big.Int
+}
+
+// MontgomeryParams is a curve with a base point, given only by its u
+// coordinate, of prime order N. Order is the order of the whole curve.
+type MontgomeryParams struct {
+	MontgomeryCurve
+	U     *big.Int
+	N     *big.Int
+	Order *big.Int
+}
+
+// Challenge60Params is v^2 = u^3 + 534u^2 + u, which is the challenge 59
+// curve in Montgomery form with u = x - 178.
+var Challenge60Params = MontgomeryParams{
+	MontgomeryCurve: MontgomeryCurve{
+		A: big.NewInt(534),
+		B: big.NewInt(1),
+		P: Challenge59Params.P,
+	},
+	U:     big.NewInt(4),
+	N:     Challenge59Params.N,
+	Order: mustDecimal("233970423115425145498902418297807005944"),
+}
+
+// TwistOrder is the order of the quadratic twist, 2p + 2 - Order. Every u
+// that isn't on the curve is on the twist.
+func (m *MontgomeryParams) TwistOrder() *big.Int {
+	t := new(big.Int).Lsh(m.P, 1)
+	t.Add(t, bigTwo)
+	return t.Sub(t, m.Order)
+}
+
+// rhs is (u^3 + Au^2 + u) / B, which has to be a square for u to be on the
+// curve.
+func (m *MontgomeryCurve) rhs(u *big.Int) *big.Int {
+	r := new(big.Int).Add(u, m.A)
+	r.Mul(r, u)
+	r.Add(r, bigOne)
+	r.Mul(r, u)
+	return m.div(r, m.B)
+}
+
+func (m *MontgomeryCurve) div(num, den *big.Int) *big.Int {
+	return (&Curve{P: m.P}).div(num, den)
+}
+
+// IsOnCurve reports whether u is the u coordinate of a point on the curve
+// rather than on its twist.
+func (m *MontgomeryCurve) IsOnCurve(u *big.Int) bool {
+	return big.Jacobi(m.rhs(u), m.P) != -1
+}
+
+// V finds a v coordinate for u. The other one is -v.
+func (m *MontgomeryCurve) V(u *big.Int) (*big.Int, bool) {
+	rhs := m.rhs(u)
+	if big.Jacobi(rhs, m.P) == -1 {
+		return nil, false
+	}
+	return new(big.Int).ModSqrt(rhs, m.P), true
+}
+
+// Weierstrass is the isomorphic short Weierstrass curve, with
+// a = (3 - A^2) / 3B^2 and b = (2A^3 - 9A) / 27B^3.
+func (m *MontgomeryCurve) Weierstrass() *Curve {
+	a2 := new(big.Int).Mul(m.A, m.A)
+	b2 := new(big.Int).Mul(m.B, m.B)
+
+	a := new(big.Int).Sub(big.NewInt(3), a2)
+	a = m.div(a, new(big.Int).Mul(b2, bigThree))
+
+	b := new(big.Int).Mul(a2, m.A)
+	b.Lsh(b, 1)
+	b.Sub(b, new(big.Int).Mul(m.A, big.NewInt(9)))
+	b = m.div(b, new(big.Int).Mul(new(big.Int).Mul(b2, m.B), big.NewInt(27)))
+
+	return &Curve{A: a, B: b, P: m.P}
+}
+
+// ToWeierstrass maps (u, v) to (u/B + A/3B, v/B).
+func (m *MontgomeryCurve) ToWeierstrass(u, v *big.Int) *Point {
+	x := new(big.Int).Mul(u, bigThree)
+	x.Add(x, m.A)
+	x = m.div(x, new(big.Int).Mul(m.B, bigThree))
+	return &Point{X: x, Y: m.div(v, m.B)}
+}
+
+// FromWeierstrass is the inverse of ToWeierstrass.
+func (m *MontgomeryCurve) FromWeierstrass(pt *Point) (*big.Int, *big.Int) {
+	u := new(big.Int).Mul(pt.X, m.B)
+	u.Sub(u, m.div(m.A, bigThree))
+	u.Mod(u, m.P)
+	v := new(big.Int).Mul(pt.Y, m.B)
+	return u, v.Mod(v, m.P)
+}
+
+// Ladder computes the u coordinate of k*(u, v) without ever knowing v. It
+// does the same work for every bit of k. The identity comes out as 0.
+func (m *MontgomeryCurve) Ladder(u, k *big.Int) *big.Int {
+	// r0 and r1 always differ by (u, 1), so the differential add works
+	base := xPoint{U: u, W: bigOne}
+	r0 := xPoint{U: bigOne, W: new(big.Int)}
+	r1 := base
+
+	bits := m.P.BitLen()
+	if k.BitLen() > bits {
+		bits = k.BitLen()
+	}
+	for i := bits - 1; i >= 0; i-- {
+		if k.Bit(i) == 1 {
+			r0, r1 = m.xAdd(r0, r1, base), m.xDouble(r1)
+		} else {
+			r0, r1 = m.xDouble(r0), m.xAdd(r0, r1, base)
+		}
+	}
+
+	return m.affine(r0)
+}
+
+type MontgomeryPrivateKey struct {
+	*MontgomeryParams
+	D   *big.Int
+	Pub *big.Int
+}
+
+func GenerateMontgomeryKey(params *MontgomeryParams) (*MontgomeryPrivateKey, error) {
+	d, err := rand.Int(rand.Reader, new(big.Int).Sub(params.N, bigOne))
+	if err != nil {
+		return nil, err
+	}
+	d.Add(d, bigOne)
+
+	return &MontgomeryPrivateKey{
+		MontgomeryParams: params,
+		D:                d,
+		Pub:              params.Ladder(params.U, d),
+	}, nil
+}
+
+// SharedSecret runs the ladder on the peer's u. It doesn't check u is on the
+// curve, and the ladder never needs to know.
+func (priv *MontgomeryPrivateKey) SharedSecret(peer *big.Int) *big.Int {
+	return priv.Ladder(peer, priv.D)
+}
+
+// XMAC is HMAC-SHA256 keyed with SHA-256 of the shared u coordinate.
+func XMAC(secret *big.Int, msg []byte) []byte {
+	key := sha256.Sum256(secret.Bytes())
+	mac := hmac.New(sha256.New, key[:])
+	mac.Write(msg)
+	return mac.Sum(nil)
+}
+
+// XMACOracle takes the attacker's public u and returns a message and its MAC
+// under the shared secret.
+type XMACOracle func(u *big.Int) ([]byte, []byte)
+
+func NewXBob(priv *MontgomeryPrivateKey) XMACOracle {
+	msg := []byte("crazy flamboyant for the rap enjoyment")
+	return func(u *big.Int) ([]byte, []byte) {
+		return msg, XMAC(priv.SharedSecret(u), msg)
+	}
+}
+
+// xPoint is a projective u coordinate U/W, with W = 0 the identity.
+type xPoint struct {
+	U, W *big.Int
+}
+
+// xDouble is 2P, the doubling half of a ladder step.
+func (m *MontgomeryCurve) xDouble(pt xPoint) xPoint {
+	p := m.P
+	uu := new(big.Int).Mul(pt.U, pt.U)
+	ww := new(big.Int).Mul(pt.W, pt.W)
+	uw := new(big.Int).Mul(pt.U, pt.W)
+
+	u := new(big.Int).Sub(uu, ww)
+	u.Mul(u, u)
+	w := new(big.Int).Mul(m.A, uw)
+	w.Add(w, uu)
+	w.Add(w, ww)
+	w.Mul(w, uw)
+	w.Lsh(w, 2)
+	return xPoint{U: u.Mod(u, p), W: w.Mod(w, p)}
+}
+
+// xAdd finds P + Q given P, Q and P - Q, the other half of a ladder step.
+func (m *MontgomeryCurve) xAdd(p1, p2, diff xPoint) xPoint {
+	p := m.P
+	u := new(big.Int).Mul(p1.U, p2.U)
+	u.Sub(u, new(big.Int).Mul(p1.W, p2.W))
+	u.Mul(u, u)
+	u.Mul(u, diff.W)
+	w := new(big.Int).Mul(p1.U, p2.W)
+	w.Sub(w, new(big.Int).Mul(p1.W, p2.U))
+	w.Mul(w, w)
+	w.Mul(w, diff.U)
+	return xPoint{U: u.Mod(u, p), W: w.Mod(w, p)}
+}
+
+func (m *MontgomeryCurve) affine(pt xPoint) *big.Int {
+	if pt.W.Sign() == 0 {
+		return new(big.Int)
+	}
+	return m.div(pt.U, pt.W)
+}
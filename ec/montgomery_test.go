@@ -0,0 +1,54 @@
+package ec
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMontgomeryToWeierstrass(t *testing.T) {
+	m := &Challenge60Params
+	w := m.Weierstrass()
+	assert.Equal(t, new(big.Int).Mod(Challenge59Params.A, m.P), w.A)
+	assert.Equal(t, Challenge59Params.B, w.B)
+
+	v, ok := m.V(m.U)
+	require.True(t, ok)
+	g := m.ToWeierstrass(m.U, v)
+	assert.Equal(t, Challenge59Params.G.X, g.X)
+	assert.True(t, w.IsOnCurve(g))
+
+	u, v2 := m.FromWeierstrass(g)
+	assert.Equal(t, m.U, u)
+	assert.Equal(t, v, v2)
+}
+
+func TestLadder(t *testing.T) {
+	m := &Challenge60Params
+	w := m.Weierstrass()
+	v, _ := m.V(m.U)
+	g := m.ToWeierstrass(m.U, v)
+
+	assert.Equal(t, 0, m.Ladder(m.U, m.N).Sign())
+	assert.Equal(t, m.U, m.Ladder(m.U, bigOne))
+
+	for i := 0; i < 5; i++ {
+		k, err := rand.Int(rand.Reader, m.N)
+		require.NoError(t, err)
+		u, _ := m.FromWeierstrass(w.ScalarMult(g, k))
+		assert.Equal(t, u, m.Ladder(m.U, k))
+	}
+}
+
+func TestXOnlyECDH(t *testing.T) {
+	alice, err := GenerateMontgomeryKey(&Challenge60Params)
+	require.NoError(t, err)
+	bob, err := GenerateMontgomeryKey(&Challenge60Params)
+	require.NoError(t, err)
+
+	assert.True(t, alice.IsOnCurve(alice.Pub))
+	assert.Equal(t, alice.SharedSecret(bob.Pub), bob.SharedSecret(alice.Pub))
+}
@@ -0,0 +1,240 @@
+package ec
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/josh-keller/cryptopals/dh"
+	"github.com/josh-keller/cryptopals/rsa"
+)
+
+// RandomTwistU picks random u until it is on the twist rather than the
+// curve.
+func (m *MontgomeryCurve) RandomTwistU() (*big.Int, error) {
+	for {
+		u, err := rand.Int(rand.Reader, m.P)
+		if err != nil {
+			return nil, err
+		}
+		if !m.IsOnCurve(u) {
+			return u, nil
+		}
+	}
+}
+
+// TwistPointOfOrder finds the u coordinate of a twist point whose order is
+// the product of the given distinct primes, all of which divide the twist
+// order. As in PointOfOrder, every power of each prime is stripped from the
+// cofactor and then each prime's part is cut down to order r.
+func (m *MontgomeryParams) TwistPointOfOrder(primes ...uint64) (*big.Int, error) {
+	cofactor := m.TwistOrder()
+	full := big.NewInt(1)
+	for _, r := range primes {
+		bigR := new(big.Int).SetUint64(r)
+		if new(big.Int).Mod(cofactor, bigR).Sign() != 0 {
+			return nil, errors.New("ec: primes do not divide the twist order")
+		}
+		for new(big.Int).Mod(cofactor, bigR).Sign() == 0 {
+			cofactor.Div(cofactor, bigR)
+			full.Mul(full, bigR)
+		}
+	}
+
+	for {
+		u, err := m.RandomTwistU()
+		if err != nil {
+			return nil, err
+		}
+		h := m.Ladder(u, cofactor)
+
+		// h has order dividing full, so shrink each prime's part to r and
+		// make sure none is missing
+		exact := true
+		for _, r := range primes {
+			bigR := new(big.Int).SetUint64(r)
+			others := new(big.Int).Div(full, bigR)
+			for new(big.Int).Mod(others, bigR).Sign() == 0 {
+				others.Div(others, bigR)
+			}
+			if m.Ladder(h, others).Sign() == 0 {
+				exact = false
+				break
+			}
+			for m.Ladder(h, new(big.Int).Mul(others, bigR)).Sign() != 0 {
+				h = m.Ladder(h, bigR)
+			}
+		}
+		if exact {
+			return h, nil
+		}
+	}
+}
+
+// xLog finds k in [0, r/2] where the MAC under k*h matches, h having order
+// r. It walks k*h with differential additions, since (k+1)h - h = (k-1)h.
+func (m *MontgomeryCurve) xLog(h *big.Int, r uint64, msg, mac []byte) (uint64, bool) {
+	base := xPoint{U: h, W: bigOne}
+	prev := xPoint{U: bigOne, W: new(big.Int)}
+	cur := base
+
+	if hmac.Equal(XMAC(new(big.Int), msg), mac) {
+		return 0, true
+	}
+	for k := uint64(1); k <= r/2; k++ {
+		if hmac.Equal(XMAC(m.affine(cur), msg), mac) {
+			return k, true
+		}
+		if k == 1 {
+			prev, cur = cur, m.xDouble(cur)
+		} else {
+			prev, cur = cur, m.xAdd(cur, base, prev)
+		}
+	}
+	return 0, false
+}
+
+// TwistResidues finds n and R with d = ±n mod R, where R is the product of
+// the odd twist factors up to bound. The ladder never checks which curve u is
+// on, so u from a small subgroup of the twist gives d mod r, but only up to
+// sign since k and -k share u. The relative signs are fixed by sending
+// points whose order is a product of two factors.
+func TwistResidues(params *MontgomeryParams, bob XMACOracle, bound uint64) (*big.Int, *big.Int, error) {
+	primes := []uint64{}
+	residues := []*big.Int{}
+	moduli := []*big.Int{}
+	product := big.NewInt(1)
+
+	for _, r := range dh.SmallFactors(params.TwistOrder(), bound) {
+		// The 2 part only leaks d mod 2, which isn't worth the trouble
+		if r == 2 {
+			continue
+		}
+		h, err := params.TwistPointOfOrder(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		msg, mac := bob(h)
+		k, ok := params.xLog(h, r, msg, mac)
+		if !ok {
+			return nil, nil, errors.New("ec: no residue matches MAC")
+		}
+
+		bigR := new(big.Int).SetUint64(r)
+		primes = append(primes, r)
+		residues = append(residues, new(big.Int).SetUint64(k))
+		moduli = append(moduli, bigR)
+		product.Mul(product, bigR)
+	}
+	if len(primes) == 0 {
+		return nil, nil, errors.New("ec: no small factors in the twist order")
+	}
+
+	// Line every residue's sign up with the first non-zero one
+	anchor := 0
+	for anchor < len(residues)-1 && residues[anchor].Sign() == 0 {
+		anchor++
+	}
+	for i := range residues {
+		if i == anchor || residues[i].Sign() == 0 {
+			continue
+		}
+		h, err := params.TwistPointOfOrder(primes[anchor], primes[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		msg, mac := bob(h)
+		k, err := rsa.CRT(
+			[]*big.Int{residues[anchor], residues[i]},
+			[]*big.Int{moduli[anchor], moduli[i]},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !hmac.Equal(XMAC(params.Ladder(h, k), msg), mac) {
+			residues[i].Sub(moduli[i], residues[i])
+		}
+	}
+
+	n, err := rsa.CRT(residues, moduli)
+	if err != nil {
+		return nil, nil, err
+	}
+	return n, product, nil
+}
+
+// TwistAttack recovers Bob's private key from an x-only ECDH oracle. The
+// twist's small factors don't cover N, so after TwistResidues the rest comes
+// from a kangaroo over d = ±n + m*R on the Weierstrass form, with Bob's
+// public u lifted to a point.
+//
+// x-only keys can't tell d from N - d either, so either may be returned.
+func TwistAttack(params *MontgomeryParams, bob XMACOracle, pub *big.Int, bound uint64) (*big.Int, error) {
+	n, product, err := TwistResidues(params, bob, bound)
+	if err != nil {
+		return nil, err
+	}
+	if product.Cmp(params.N) > 0 {
+		return n.Mod(n, params.N), nil
+	}
+	return twistKangaroo(params, pub, n, product)
+}
+
+// twistKangaroo finds d with d = ±n mod product and d*U = ±pub, returning d
+// or N - d.
+func twistKangaroo(params *MontgomeryParams, pub, n, product *big.Int) (*big.Int, error) {
+	curve := params.Weierstrass()
+	gv, ok := params.V(params.U)
+	if !ok {
+		return nil, errors.New("ec: base point is not on the curve")
+	}
+	yv, ok := params.V(pub)
+	if !ok {
+		return nil, errors.New("ec: public key is not on the curve")
+	}
+	g := params.ToWeierstrass(params.U, gv)
+	y := params.ToWeierstrass(pub, yv)
+	gPrime := curve.ScalarMult(g, product)
+
+	upper := new(big.Int).Div(new(big.Int).Sub(params.N, bigOne), product)
+	upper.Add(upper, bigOne)
+	if !upper.IsUint64() || upper.Uint64() >= 1<<62 {
+		return nil, errors.New("ec: interval too large for the kangaroo")
+	}
+
+	// Lifting pub picked the sign of v at random, so y = ±d*g. Writing
+	// d = ±n + m*R, the log of y is one of ±n + m*R for m in [-upper, upper].
+	// Shifting by upper*R*g puts m back into [0, 2*upper] and both wild
+	// kangaroos share the tame one's trap.
+	shift := curve.ScalarMult(gPrime, upper)
+	candidates := []*big.Int{n}
+	if n.Sign() != 0 {
+		candidates = append(candidates, new(big.Int).Sub(product, n))
+	}
+	starts := make([]*Point, len(candidates))
+	for i, s := range candidates {
+		start := curve.Add(y, curve.ScalarMult(g, new(big.Int).Neg(s)))
+		starts[i] = curve.Add(start, shift)
+	}
+
+	for attempt := 0; attempt < dh.KangarooAttempts; attempt++ {
+		m, i, err := curve.kangaroo(gPrime, starts, 0, 2*upper.Uint64(), dh.RetryOptions(attempt))
+		if errors.Is(err, dh.ErrKangarooEscaped) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		d := m.Sub(m, upper)
+		d.Mul(d, product)
+		d.Add(d, candidates[i])
+		d.Mod(d, params.N)
+		if params.Ladder(params.U, d).Cmp(pub) == 0 {
+			return d, nil
+		}
+	}
+
+	return nil, dh.ErrKangarooEscaped
+}
@@ -0,0 +1,115 @@
+package ec
+
+import (
+	"crypto/rand"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/josh-keller/cryptopals/dh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwistPointOfOrder(t *testing.T) {
+	m := &Challenge60Params
+	assert.Equal(t, mustDecimal("233970423115425145549737651362517029924"), m.TwistOrder())
+
+	for _, primes := range [][]uint64{{11}, {107}, {11, 1621}} {
+		h, err := m.TwistPointOfOrder(primes...)
+		require.NoError(t, err)
+		assert.False(t, m.IsOnCurve(h))
+
+		order := big.NewInt(1)
+		for _, r := range primes {
+			order.Mul(order, new(big.Int).SetUint64(r))
+		}
+		assert.Equal(t, 0, m.Ladder(h, order).Sign())
+	}
+}
+
+func TestTwistPointOfOrderRepeatedFactor(t *testing.T) {
+	// The twist of v^2 = u^3 + 48u^2 + u over GF(1019) has order 968 = 8 * 11^2
+	m := &MontgomeryParams{
+		MontgomeryCurve: MontgomeryCurve{A: big.NewInt(48), B: big.NewInt(1), P: big.NewInt(1019)},
+		Order:           big.NewInt(1072),
+	}
+	require.Equal(t, big.NewInt(968), m.TwistOrder())
+
+	for i := 0; i < 20; i++ {
+		h, err := m.TwistPointOfOrder(11)
+		require.NoError(t, err)
+		assert.NotEqual(t, 0, h.Sign())
+		assert.Equal(t, 0, m.Ladder(h, big.NewInt(11)).Sign())
+	}
+}
+
+func TestXLog(t *testing.T) {
+	m := &Challenge60Params
+	h, err := m.TwistPointOfOrder(1621)
+	require.NoError(t, err)
+
+	msg := []byte("hello")
+	for _, k := range []int64{0, 1, 2, 700, 1620} {
+		mac := XMAC(m.Ladder(h, big.NewInt(k)), msg)
+		got, ok := m.xLog(h, 1621, msg, mac)
+		require.True(t, ok)
+		assert.Contains(t, []uint64{uint64(k), uint64(1621 - k)}, got)
+	}
+}
+
+func TestECKangaroo(t *testing.T) {
+	c := &Challenge59Params
+	x, err := rand.Int(rand.Reader, big.NewInt(1<<20))
+	require.NoError(t, err)
+	x.Add(x, big.NewInt(1000))
+	y := c.ScalarMult(c.G, x)
+
+	got, err := c.Kangaroo(c.G, y, 1000, 1000+1<<20, dh.KangarooOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, x, got)
+}
+
+func TestTwistResidues(t *testing.T) {
+	priv, err := GenerateMontgomeryKey(&Challenge60Params)
+	require.NoError(t, err)
+
+	n, r, err := TwistResidues(&Challenge60Params, NewXBob(priv), 1<<22)
+	require.NoError(t, err)
+	assert.Equal(t, 85, r.BitLen())
+	assert.Contains(t, []*big.Int{
+		new(big.Int).Mod(priv.D, r),
+		new(big.Int).Mod(new(big.Int).Neg(priv.D), r),
+	}, n)
+}
+
+func TestTwistKangaroo(t *testing.T) {
+	params := &Challenge60Params
+	for i := 0; i < 4; i++ {
+		priv, err := GenerateMontgomeryKey(params)
+		require.NoError(t, err)
+
+		// Pretend the residues covered all but 20 bits of N, with either sign
+		product := new(big.Int).Rsh(params.N, 20)
+		n := new(big.Int).Mod(priv.D, product)
+		if i%2 == 1 {
+			n.Sub(product, n)
+		}
+
+		d, err := twistKangaroo(params, priv.Pub, n, product)
+		require.NoError(t, err)
+		assert.Contains(t, []*big.Int{priv.D, new(big.Int).Sub(priv.N, priv.D)}, d)
+	}
+}
+
+func TestTwistAttack(t *testing.T) {
+	if os.Getenv("CRYPTOPALS_LONG") == "" {
+		t.Skip("kangaroo over a 41 bit interval, set CRYPTOPALS_LONG=1 to run")
+	}
+	priv, err := GenerateMontgomeryKey(&Challenge60Params)
+	require.NoError(t, err)
+
+	d, err := TwistAttack(&Challenge60Params, NewXBob(priv), priv.Pub, 1<<22)
+	require.NoError(t, err)
+	assert.Contains(t, []*big.Int{priv.D, new(big.Int).Sub(priv.N, priv.D)}, d)
+}
@@ -0,0 +1,98 @@
+package gcm
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"errors"
+
+	"github.com/josh-keller/cryptopals/gf128"
+)
+
+// Message is a captured GCM message: its nonce, additional data and the
+// ciphertext with the tag appended.
+type Message struct {
+	Nonce, AD, Sealed []byte
+}
+
+func (m Message) split() ([]byte, []byte) {
+	return m.Sealed[:len(m.Sealed)-TagSize], m.Sealed[len(m.Sealed)-TagSize:]
+}
+
+// tagPoly is the GHASH polynomial in H with the tag added to the constant
+// term, so that at the real H it evaluates to s = E(J0).
+func tagPoly(m Message) gf128.Poly {
+	ct, tag := m.split()
+	bs := append(blocks(m.AD), blocks(ct)...)
+	bs = append(bs, lengthBlock(m.AD, ct))
+
+	p := make(gf128.Poly, len(bs)+1)
+	p[0] = gf128.FromBytes(tag)
+	for i, b := range bs {
+		p[len(bs)-i] = b
+	}
+	return gf128.NewPoly(p...)
+}
+
+// AuthKey is everything needed to compute tags under one nonce: the GHASH
+// key H and the mask s = E(J0).
+type AuthKey struct {
+	H, S gf128.Element
+}
+
+func (k AuthKey) Tag(ad, ct []byte) []byte {
+	return GHASH(k.H, ad, ct).Add(k.S).Bytes()
+}
+
+// Forge appends a valid tag to an arbitrary ciphertext under the key's
+// nonce.
+func (k AuthKey) Forge(ad, ct []byte) []byte {
+	return append(append([]byte{}, ct...), k.Tag(ad, ct)...)
+}
+
+// RecoverAuthKey is the forbidden attack. Two messages under the same nonce
+// share s, so subtracting their tag polynomials cancels it and leaves a
+// polynomial with H as a root. Its roots are candidates for H, and each
+// fixes s. Any further messages under the same nonce weed out the wrong
+// ones. Usually one key is left, but every survivor is returned.
+func RecoverAuthKey(m1, m2 Message, others ...Message) ([]AuthKey, error) {
+	for _, m := range append([]Message{m1, m2}, others...) {
+		if len(m.Sealed) < TagSize {
+			return nil, errors.New("gcm: message too short for a tag")
+		}
+		if !bytes.Equal(m.Nonce, m1.Nonce) {
+			return nil, errors.New("gcm: messages must share a nonce")
+		}
+	}
+
+	p1 := tagPoly(m1)
+	diff := p1.Add(tagPoly(m2))
+	if diff.Degree() < 1 {
+		return nil, errors.New("gcm: messages are identical")
+	}
+
+	roots, err := gf128.Roots(diff)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []AuthKey{}
+	for _, h := range roots {
+		key := AuthKey{H: h, S: p1.Eval(h)}
+		valid := true
+		for _, m := range others {
+			ct, tag := m.split()
+			if subtle.ConstantTimeCompare(key.Tag(m.AD, ct), tag) != 1 {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("gcm: no candidate key survived")
+	}
+	return keys, nil
+}
@@ -0,0 +1,81 @@
+package gcm
+
+import (
+	"testing"
+
+	"github.com/josh-keller/cryptopals/gf128"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagPoly(t *testing.T) {
+	g, err := New(randomBytes(t, 16))
+	require.NoError(t, err)
+	nonce := randomBytes(t, 12)
+	m := Message{Nonce: nonce, AD: []byte("ad"), Sealed: g.Seal(nonce, randomBytes(t, 40), []byte("ad"))}
+
+	s := make([]byte, BlockSize)
+	g.block.Encrypt(s, g.j0(nonce))
+	assert.Equal(t, gf128.FromBytes(s), tagPoly(m).Eval(g.h))
+}
+
+func TestForbiddenAttack(t *testing.T) {
+	g, err := New(randomBytes(t, 16))
+	require.NoError(t, err)
+	nonce := randomBytes(t, 12)
+
+	seal := func(pt, ad string) Message {
+		return Message{Nonce: nonce, AD: []byte(ad), Sealed: g.Seal(nonce, []byte(pt), []byte(ad))}
+	}
+	m1 := seal("Transfer $10 to Bob, memo: lunch money", "from=alice")
+	m2 := seal("Transfer $25 to Carol for the concert tickets", "from=alice")
+	m3 := seal("Transfer $5 to Dave", "from=alice")
+
+	t.Run("Candidates include H", func(t *testing.T) {
+		keys, err := RecoverAuthKey(m1, m2)
+		require.NoError(t, err)
+		hs := []gf128.Element{}
+		for _, k := range keys {
+			hs = append(hs, k.H)
+		}
+		assert.Contains(t, hs, g.h)
+	})
+
+	keys, err := RecoverAuthKey(m1, m2, m3)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	key := keys[0]
+	assert.Equal(t, g.h, key.H)
+
+	t.Run("Forge an arbitrary ciphertext", func(t *testing.T) {
+		ct := randomBytes(t, 50)
+		forged := key.Forge([]byte("anything"), ct)
+		pt, err := g.Open(nonce, forged, []byte("anything"))
+		require.NoError(t, err)
+		assert.Len(t, pt, 50)
+	})
+
+	t.Run("Rewrite a known plaintext", func(t *testing.T) {
+		// The keystream is shared too, so a known plaintext can be swapped
+		known := []byte("Transfer $5 to Dave")
+		wanted := []byte("Transfer $9999 to E")
+		ct, _ := m3.split()
+		ct = append([]byte{}, ct...)
+		for i := range ct {
+			ct[i] ^= known[i] ^ wanted[i]
+		}
+
+		pt, err := g.Open(nonce, key.Forge([]byte("from=alice"), ct), []byte("from=alice"))
+		require.NoError(t, err)
+		assert.Equal(t, wanted, pt)
+	})
+
+	t.Run("Different nonces", func(t *testing.T) {
+		other := randomBytes(t, 12)
+		m4 := Message{Nonce: other, Sealed: g.Seal(other, []byte("hi"), nil)}
+		_, err := RecoverAuthKey(m1, m4)
+		assert.Error(t, err)
+		_, err = RecoverAuthKey(m1, m1)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,128 @@
+package gcm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+
+	"github.com/josh-keller/cryptopals/gf128"
+)
+
+const (
+	BlockSize = 16
+	TagSize   = 16
+)
+
+var ErrOpen = errors.New("gcm: message authentication failed")
+
+// GCM is AES in Galois/Counter Mode: CTR encryption keyed from a counter
+// block J0, plus a GHASH authenticator keyed with H = E(0^128).
+type GCM struct {
+	block cipher.Block
+	h     gf128.Element
+}
+
+func New(key []byte) (*GCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	h := make([]byte, BlockSize)
+	block.Encrypt(h, h)
+	return &GCM{block: block, h: gf128.FromBytes(h)}, nil
+}
+
+// blocks splits b into 16 byte blocks, zero padding the last.
+func blocks(b []byte) []gf128.Element {
+	out := []gf128.Element{}
+	for i := 0; i < len(b); i += BlockSize {
+		block := make([]byte, BlockSize)
+		copy(block, b[i:])
+		out = append(out, gf128.FromBytes(block))
+	}
+	return out
+}
+
+// lengthBlock is len(ad) || len(ct) in bits, 64 bits each.
+func lengthBlock(ad, ct []byte) gf128.Element {
+	return gf128.Element{Hi: uint64(len(ad)) * 8, Lo: uint64(len(ct)) * 8}
+}
+
+// GHASH evaluates the polynomial whose coefficients are the blocks of ad,
+// the blocks of ct and the length block at h, by Horner's rule:
+// g = (g + block) * h for each block.
+func GHASH(h gf128.Element, ad, ct []byte) gf128.Element {
+	var g gf128.Element
+	for _, b := range blocks(ad) {
+		g = g.Add(b).Mul(h)
+	}
+	for _, b := range blocks(ct) {
+		g = g.Add(b).Mul(h)
+	}
+	return g.Add(lengthBlock(ad, ct)).Mul(h)
+}
+
+// j0 is nonce || 0^31 || 1 for the usual 96 bit nonce, otherwise the GHASH
+// of the nonce.
+func (g *GCM) j0(nonce []byte) []byte {
+	if len(nonce) == 12 {
+		j := make([]byte, BlockSize)
+		copy(j, nonce)
+		j[BlockSize-1] = 1
+		return j
+	}
+	return GHASH(g.h, nil, nonce).Bytes()
+}
+
+// inc32 increments the last 32 bits of the counter block, big endian and
+// wrapping, leaving the rest alone.
+func inc32(counter []byte) {
+	c := binary.BigEndian.Uint32(counter[BlockSize-4:])
+	binary.BigEndian.PutUint32(counter[BlockSize-4:], c+1)
+}
+
+// ctr encrypts text with the keystream starting from the block after j0.
+func (g *GCM) ctr(j0, text []byte) []byte {
+	counter := append([]byte{}, j0...)
+	keystream := make([]byte, BlockSize)
+	out := make([]byte, len(text))
+
+	for i := 0; i < len(text); i += BlockSize {
+		inc32(counter)
+		g.block.Encrypt(keystream, counter)
+		for j := i; j < len(text) && j < i+BlockSize; j++ {
+			out[j] = text[j] ^ keystream[j-i]
+		}
+	}
+	return out
+}
+
+func (g *GCM) tag(j0, ad, ct []byte) []byte {
+	s := make([]byte, BlockSize)
+	g.block.Encrypt(s, j0)
+	return GHASH(g.h, ad, ct).Add(gf128.FromBytes(s)).Bytes()
+}
+
+// Seal encrypts and authenticates plaintext and authenticates ad, returning
+// the ciphertext with the tag appended.
+func (g *GCM) Seal(nonce, plaintext, ad []byte) []byte {
+	j0 := g.j0(nonce)
+	ct := g.ctr(j0, plaintext)
+	return append(ct, g.tag(j0, ad, ct)...)
+}
+
+// Open checks the tag before decrypting anything.
+func (g *GCM) Open(nonce, sealed, ad []byte) ([]byte, error) {
+	if len(sealed) < TagSize {
+		return nil, ErrOpen
+	}
+	ct, tag := sealed[:len(sealed)-TagSize], sealed[len(sealed)-TagSize:]
+
+	j0 := g.j0(nonce)
+	if subtle.ConstantTimeCompare(tag, g.tag(j0, ad, ct)) != 1 {
+		return nil, ErrOpen
+	}
+	return g.ctr(j0, ct), nil
+}
@@ -0,0 +1,81 @@
+package gcm
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomBytes(t *testing.T, n int) []byte {
+	b := make([]byte, n)
+	_, err := rand.Read(b)
+	require.NoError(t, err)
+	return b
+}
+
+func TestGCMTestCase2(t *testing.T) {
+	g, err := New(make([]byte, 16))
+	require.NoError(t, err)
+
+	sealed := g.Seal(make([]byte, 12), make([]byte, 16), nil)
+	assert.Equal(t, "0388dace60b6a392f328c2b971b2fe78ab6e47d42cec13bdf53a67b21257bddf", hex.EncodeToString(sealed))
+}
+
+func TestGCMMatchesStandardLibrary(t *testing.T) {
+	for _, keySize := range []int{16, 24, 32} {
+		for _, nonceSize := range []int{12, 8, 16} {
+			for _, ptLen := range []int{0, 1, 15, 16, 17, 64, 100} {
+				key := randomBytes(t, keySize)
+				nonce := randomBytes(t, nonceSize)
+				pt := randomBytes(t, ptLen)
+				ad := randomBytes(t, ptLen%7*5)
+
+				block, err := aes.NewCipher(key)
+				require.NoError(t, err)
+				std, err := cipher.NewGCMWithNonceSize(block, nonceSize)
+				require.NoError(t, err)
+				g, err := New(key)
+				require.NoError(t, err)
+
+				sealed := g.Seal(nonce, pt, ad)
+				assert.Equal(t, std.Seal(nil, nonce, pt, ad), sealed,
+					"key %d nonce %d plaintext %d", keySize, nonceSize, ptLen)
+
+				opened, err := g.Open(nonce, sealed, ad)
+				require.NoError(t, err)
+				assert.Equal(t, pt, opened)
+			}
+		}
+	}
+}
+
+func TestGCMRejectsTampering(t *testing.T) {
+	g, err := New(randomBytes(t, 16))
+	require.NoError(t, err)
+	nonce := randomBytes(t, 12)
+	ad := []byte("header")
+	sealed := g.Seal(nonce, []byte("attack at dawn"), ad)
+
+	for i := range sealed {
+		tampered := append([]byte{}, sealed...)
+		tampered[i] ^= 1
+		_, err := g.Open(nonce, tampered, ad)
+		assert.ErrorIs(t, err, ErrOpen)
+	}
+
+	_, err = g.Open(nonce, sealed, []byte("Header"))
+	assert.ErrorIs(t, err, ErrOpen)
+	_, err = g.Open(nonce, sealed[:10], ad)
+	assert.ErrorIs(t, err, ErrOpen)
+}
+
+func TestInc32Wraps(t *testing.T) {
+	counter := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 0xff, 0xff, 0xff, 0xff}
+	inc32(counter)
+	assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 0, 0, 0, 0}, counter)
+}
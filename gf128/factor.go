@@ -0,0 +1,158 @@
+package gf128
+
+// Factor is a polynomial together with a count: the multiplicity for
+// SquareFree and the degree of every irreducible factor for DistinctDegree.
+type Factor struct {
+	Poly Poly
+	N    int
+}
+
+// sqrt undoes squaring a polynomial whose derivative is zero. Only even
+// powers are present, and (sum a_i x^i)^2 = sum a_i^2 x^2i.
+func (p Poly) sqrt() Poly {
+	root := make(Poly, (len(p)+1)/2)
+	for i := range root {
+		root[i] = p[2*i].Sqrt()
+	}
+	return root.trim()
+}
+
+// SquareFree splits a monic f into square-free factors, each paired with its
+// multiplicity, so f is the product of Poly^N. This is the usual algorithm
+// over GF(q): gcd(f, f') collects the repeated factors, except that those
+// with multiplicity divisible by 2 have a zero derivative and are handled by
+// taking a square root and recursing.
+func SquareFree(f Poly) []Factor {
+	f = f.Monic()
+	factors := []Factor{}
+
+	c := GCD(f, f.Derivative())
+	w := f.Div(c)
+	for i := 1; !w.IsOne(); i++ {
+		y := GCD(w, c)
+		if fac := w.Div(y); !fac.IsOne() {
+			factors = append(factors, Factor{Poly: fac, N: i})
+		}
+		w = y
+		c = c.Div(y)
+	}
+
+	if !c.IsOne() {
+		for _, fac := range SquareFree(c.sqrt()) {
+			factors = append(factors, Factor{Poly: fac.Poly, N: fac.N * 2})
+		}
+	}
+	return factors
+}
+
+// frobenius is h^(2^128) mod f, 128 squarings.
+func frobenius(h, f Poly) Poly {
+	for i := 0; i < 128; i++ {
+		h = h.Mul(h).Mod(f)
+	}
+	return h
+}
+
+// DistinctDegree splits a monic square-free f into factors whose irreducible
+// factors all have degree N. x^(q^i) - x is the product of every monic
+// irreducible of degree dividing i, so its gcd with what is left of f picks
+// out the degree i factors.
+func DistinctDegree(f Poly) []Factor {
+	f = f.Monic()
+	factors := []Factor{}
+	x := X(1)
+
+	h := x.Mod(f)
+	for i := 1; f.Degree() >= 2*i; i++ {
+		h = frobenius(h, f)
+		g := GCD(f, h.Add(x))
+		if !g.IsOne() {
+			factors = append(factors, Factor{Poly: g, N: i})
+			f = f.Div(g)
+			h = h.Mod(f)
+		}
+	}
+
+	if f.Degree() > 0 {
+		factors = append(factors, Factor{Poly: f, N: f.Degree()})
+	}
+	return factors
+}
+
+// randomBelow picks a polynomial of degree below n.
+func randomBelow(n int) (Poly, error) {
+	p := make(Poly, n)
+	for i := range p {
+		var err error
+		if p[i], err = Random(); err != nil {
+			return nil, err
+		}
+	}
+	return p.trim(), nil
+}
+
+// EqualDegree is Cantor-Zassenhaus: it splits a monic square-free f, all of
+// whose irreducible factors have degree d, into those factors. The usual
+// a^((q^d-1)/2) doesn't work in characteristic 2, so it uses the trace
+// a + a^2 + ... + a^(2^(128d-1)) instead, which is 0 or 1 modulo each
+// factor, so its gcd with f splits f about half the time.
+func EqualDegree(f Poly, d int) ([]Poly, error) {
+	f = f.Monic()
+	n := f.Degree() / d
+	factors := []Poly{f}
+
+	for len(factors) < n {
+		a, err := randomBelow(f.Degree())
+		if err != nil {
+			return nil, err
+		}
+		t, sq := a, a
+		for i := 1; i < 128*d; i++ {
+			sq = sq.Mul(sq).Mod(f)
+			t = t.Add(sq)
+		}
+
+		next := []Poly{}
+		for _, u := range factors {
+			if u.Degree() == d {
+				next = append(next, u)
+				continue
+			}
+			g := GCD(u, t)
+			if g.IsOne() || g.Degree() == u.Degree() {
+				next = append(next, u)
+				continue
+			}
+			next = append(next, g, u.Div(g))
+		}
+		factors = next
+	}
+	return factors, nil
+}
+
+// Roots returns the distinct roots of f in GF(2^128), found by pulling out
+// the linear factors with SquareFree and DistinctDegree and then splitting
+// them with EqualDegree.
+func Roots(f Poly) ([]Element, error) {
+	roots := []Element{}
+	seen := map[Element]bool{}
+	for _, sf := range SquareFree(f) {
+		for _, dd := range DistinctDegree(sf.Poly) {
+			if dd.N != 1 {
+				continue
+			}
+			linears, err := EqualDegree(dd.Poly, 1)
+			if err != nil {
+				return nil, err
+			}
+			// Each factor is x + r
+			for _, l := range linears {
+				if r := l[0]; !seen[r] {
+					seen[r] = true
+					roots = append(roots, r)
+				}
+			}
+		}
+	}
+	return roots, nil
+}
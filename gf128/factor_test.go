@@ -0,0 +1,118 @@
+package gf128
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trace is a + a^2 + a^4 + ... + a^(2^127), which is always 0 or 1.
+func trace(a Element) Element {
+	t, sq := a, a
+	for i := 1; i < 128; i++ {
+		sq = sq.Mul(sq)
+		t = t.Add(sq)
+	}
+	return t
+}
+
+// irreducibleQuadratic returns x^2 + x + c with Tr(c) = 1, which has no
+// roots in GF(2^128).
+func irreducibleQuadratic(t *testing.T) Poly {
+	for {
+		c := randomElements(t, 1)[0]
+		if trace(c) == One {
+			return NewPoly(c, One, One)
+		}
+	}
+}
+
+func linear(a Element) Poly {
+	return NewPoly(a, One)
+}
+
+func TestSquareFree(t *testing.T) {
+	e := randomElements(t, 4)
+	a, b, c, d := linear(e[0]), linear(e[1]), linear(e[2]), linear(e[3])
+	// a * b^2 * c^3 * d^4
+	f := a.Mul(b).Mul(b).Mul(c).Mul(c).Mul(c).Mul(d).Mul(d).Mul(d).Mul(d)
+
+	factors := SquareFree(f)
+	product := NewPoly(One)
+	byN := map[int]Poly{}
+	for _, fac := range factors {
+		assert.True(t, GCD(fac.Poly, fac.Poly.Derivative()).IsOne(), "factor %d not square-free", fac.N)
+		byN[fac.N] = fac.Poly
+		for i := 0; i < fac.N; i++ {
+			product = product.Mul(fac.Poly)
+		}
+	}
+	assert.True(t, product.Equal(f))
+	assert.Len(t, factors, 4)
+	assert.True(t, byN[1].Equal(a))
+	assert.True(t, byN[2].Equal(b))
+	assert.True(t, byN[3].Equal(c))
+	assert.True(t, byN[4].Equal(d))
+}
+
+func TestDistinctDegree(t *testing.T) {
+	e := randomElements(t, 3)
+	linears := linear(e[0]).Mul(linear(e[1])).Mul(linear(e[2]))
+	quadratics := irreducibleQuadratic(t).Mul(irreducibleQuadratic(t))
+
+	factors := DistinctDegree(linears.Mul(quadratics))
+	assert.Len(t, factors, 2)
+	assert.Equal(t, 1, factors[0].N)
+	assert.True(t, factors[0].Poly.Equal(linears))
+	assert.Equal(t, 2, factors[1].N)
+	assert.True(t, factors[1].Poly.Equal(quadratics))
+}
+
+func TestEqualDegree(t *testing.T) {
+	t.Run("Linear factors", func(t *testing.T) {
+		e := randomElements(t, 5)
+		f := NewPoly(One)
+		for _, a := range e {
+			f = f.Mul(linear(a))
+		}
+
+		factors, err := EqualDegree(f, 1)
+		require.NoError(t, err)
+		assert.Len(t, factors, 5)
+		found := []Element{}
+		for _, fac := range factors {
+			assert.Equal(t, 1, fac.Degree())
+			found = append(found, fac[0])
+		}
+		assert.ElementsMatch(t, e, found)
+	})
+
+	t.Run("Quadratic factors", func(t *testing.T) {
+		q1, q2, q3 := irreducibleQuadratic(t), irreducibleQuadratic(t), irreducibleQuadratic(t)
+		factors, err := EqualDegree(q1.Mul(q2).Mul(q3), 2)
+		require.NoError(t, err)
+		assert.Len(t, factors, 3)
+		for _, q := range []Poly{q1, q2, q3} {
+			found := false
+			for _, fac := range factors {
+				found = found || fac.Equal(q)
+			}
+			assert.True(t, found)
+		}
+	})
+}
+
+func TestRoots(t *testing.T) {
+	e := randomElements(t, 3)
+	// (x + a)^2 (x + b) (x + c) times something with no roots
+	f := linear(e[0]).Mul(linear(e[0])).Mul(linear(e[1])).Mul(linear(e[2]))
+	f = f.Mul(irreducibleQuadratic(t)).Scale(e[1])
+
+	roots, err := Roots(f)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, e, roots)
+	for _, r := range roots {
+		assert.True(t, f.Eval(r).IsZero())
+	}
+}
@@ -0,0 +1,112 @@
+package gf128
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+)
+
+// Element is a member of GF(2^128) defined by x^128 + x^7 + x^2 + x + 1, in
+// GCM's bit order: the most significant bit of Hi is the coefficient of x^0
+// and the least significant bit of Lo is the coefficient of x^127.
+type Element struct {
+	Hi, Lo uint64
+}
+
+var (
+	Zero = Element{}
+	One  = Element{Hi: 1 << 63}
+)
+
+// r is x^7 + x^2 + x + 1 in GCM order, what x^128 reduces to.
+const r = 0xe1 << 56
+
+// FromBytes reads a 16 byte block.
+func FromBytes(b []byte) Element {
+	return Element{
+		Hi: binary.BigEndian.Uint64(b[:8]),
+		Lo: binary.BigEndian.Uint64(b[8:16]),
+	}
+}
+
+func (a Element) Bytes() []byte {
+	b := make([]byte, 16)
+	binary.BigEndian.PutUint64(b, a.Hi)
+	binary.BigEndian.PutUint64(b[8:], a.Lo)
+	return b
+}
+
+func (a Element) String() string {
+	return fmt.Sprintf("%016x%016x", a.Hi, a.Lo)
+}
+
+func Random() (Element, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return Zero, err
+	}
+	return FromBytes(b), nil
+}
+
+func (a Element) IsZero() bool {
+	return a == Zero
+}
+
+// Add is XOR, and so is subtraction.
+func (a Element) Add(b Element) Element {
+	return Element{Hi: a.Hi ^ b.Hi, Lo: a.Lo ^ b.Lo}
+}
+
+// Mul is the shift and add multiplication from NIST SP 800-38D. Shifting
+// right multiplies by x in this bit order.
+func (a Element) Mul(b Element) Element {
+	var z Element
+	v := b
+	for i := 0; i < 128; i++ {
+		word := a.Hi
+		if i >= 64 {
+			word = a.Lo
+		}
+		if word&(1<<(63-i%64)) != 0 {
+			z = z.Add(v)
+		}
+
+		carry := v.Lo & 1
+		v.Lo = v.Lo>>1 | v.Hi<<63
+		v.Hi >>= 1
+		if carry != 0 {
+			v.Hi ^= r
+		}
+	}
+	return z
+}
+
+// Pow raises a to e by square and multiply, e being up to 128 bits as hi, lo.
+func (a Element) Pow(hi, lo uint64) Element {
+	result := One
+	for _, word := range []uint64{hi, lo} {
+		for i := 63; i >= 0; i-- {
+			result = result.Mul(result)
+			if word&(1<<i) != 0 {
+				result = result.Mul(a)
+			}
+		}
+	}
+	return result
+}
+
+// Inv is a^(2^128 - 2). It panics on zero.
+func (a Element) Inv() Element {
+	if a.IsZero() {
+		panic("gf128: inverse of zero")
+	}
+	return a.Pow(^uint64(0), ^uint64(0)-1)
+}
+
+// Sqrt is a^(2^127), since squaring is a bijection in characteristic 2.
+func (a Element) Sqrt() Element {
+	for i := 0; i < 127; i++ {
+		a = a.Mul(a)
+	}
+	return a
+}
@@ -0,0 +1,60 @@
+package gf128
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustElement(s string) Element {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return FromBytes(b)
+}
+
+func randomElements(t *testing.T, n int) []Element {
+	elems := make([]Element, n)
+	for i := range elems {
+		var err error
+		elems[i], err = Random()
+		require.NoError(t, err)
+	}
+	return elems
+}
+
+func TestMul(t *testing.T) {
+	t.Run("GCM test case 2", func(t *testing.T) {
+		// GHASH of one ciphertext block is ((C*H) + len)*H
+		h := mustElement("66e94bd4ef8a2c3b884cfa59ca342b2e")
+		c := mustElement("0388dace60b6a392f328c2b971b2fe78")
+		lengths := mustElement("00000000000000000000000000000080")
+		assert.Equal(t, mustElement("f38cbb1ad69223dcc3457ae5b6b0f885"), c.Mul(h).Add(lengths).Mul(h))
+	})
+
+	t.Run("Field axioms", func(t *testing.T) {
+		e := randomElements(t, 3)
+		a, b, c := e[0], e[1], e[2]
+		assert.Equal(t, a.Mul(b), b.Mul(a))
+		assert.Equal(t, a.Mul(b).Mul(c), a.Mul(b.Mul(c)))
+		assert.Equal(t, a.Mul(b.Add(c)), a.Mul(b).Add(a.Mul(c)))
+		assert.Equal(t, a, a.Mul(One))
+		assert.Equal(t, Zero, a.Mul(Zero))
+	})
+
+	t.Run("Inverse and square root", func(t *testing.T) {
+		for _, a := range randomElements(t, 4) {
+			assert.Equal(t, One, a.Mul(a.Inv()))
+			assert.Equal(t, a, a.Sqrt().Mul(a.Sqrt()))
+		}
+		assert.Panics(t, func() { Zero.Inv() })
+	})
+
+	t.Run("Bytes round trip", func(t *testing.T) {
+		a := randomElements(t, 1)[0]
+		assert.Equal(t, a, FromBytes(a.Bytes()))
+	})
+}
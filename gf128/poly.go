@@ -0,0 +1,198 @@
+package gf128
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Poly is a polynomial over GF(2^128) with the constant term first. Results
+// are always trimmed so the last coefficient is non-zero, and the zero
+// polynomial is empty.
+type Poly []Element
+
+// NewPoly copies coeffs, constant term first, and trims the result.
+func NewPoly(coeffs ...Element) Poly {
+	return Poly(append([]Element{}, coeffs...)).trim()
+}
+
+// X is the monomial x^n.
+func X(n int) Poly {
+	p := make(Poly, n+1)
+	p[n] = One
+	return p
+}
+
+func (p Poly) trim() Poly {
+	n := len(p)
+	for n > 0 && p[n-1].IsZero() {
+		n--
+	}
+	return p[:n]
+}
+
+// Degree is -1 for the zero polynomial.
+func (p Poly) Degree() int {
+	return len(p.trim()) - 1
+}
+
+func (p Poly) IsZero() bool {
+	return p.Degree() < 0
+}
+
+func (p Poly) IsOne() bool {
+	return p.Degree() == 0 && p[0] == One
+}
+
+func (p Poly) Equal(q Poly) bool {
+	p, q = p.trim(), q.trim()
+	if len(p) != len(q) {
+		return false
+	}
+	for i := range p {
+		if p[i] != q[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Poly) String() string {
+	terms := []string{}
+	for i := len(p) - 1; i >= 0; i-- {
+		if !p[i].IsZero() {
+			terms = append(terms, p[i].String()+"*x^"+strconv.Itoa(i))
+		}
+	}
+	if len(terms) == 0 {
+		return "0"
+	}
+	return strings.Join(terms, " + ")
+}
+
+// Add is also subtraction.
+func (p Poly) Add(q Poly) Poly {
+	if len(p) < len(q) {
+		p, q = q, p
+	}
+	sum := append(Poly{}, p...)
+	for i := range q {
+		sum[i] = sum[i].Add(q[i])
+	}
+	return sum.trim()
+}
+
+func (p Poly) Mul(q Poly) Poly {
+	p, q = p.trim(), q.trim()
+	if len(p) == 0 || len(q) == 0 {
+		return Poly{}
+	}
+	prod := make(Poly, len(p)+len(q)-1)
+	for i, a := range p {
+		if a.IsZero() {
+			continue
+		}
+		for j, b := range q {
+			prod[i+j] = prod[i+j].Add(a.Mul(b))
+		}
+	}
+	return prod.trim()
+}
+
+// Scale multiplies every coefficient by a.
+func (p Poly) Scale(a Element) Poly {
+	out := make(Poly, len(p))
+	for i := range p {
+		out[i] = p[i].Mul(a)
+	}
+	return out.trim()
+}
+
+// Monic divides through by the leading coefficient.
+func (p Poly) Monic() Poly {
+	p = p.trim()
+	if len(p) == 0 {
+		return p
+	}
+	return p.Scale(p[len(p)-1].Inv())
+}
+
+// DivMod is long division. It panics if q is zero.
+func (p Poly) DivMod(q Poly) (Poly, Poly) {
+	q = q.trim()
+	if len(q) == 0 {
+		panic("gf128: division by zero polynomial")
+	}
+	rem := append(Poly{}, p.trim()...)
+	if len(rem) < len(q) {
+		return Poly{}, rem
+	}
+
+	quo := make(Poly, len(rem)-len(q)+1)
+	leadInv := q[len(q)-1].Inv()
+	for d := len(rem) - len(q); d >= 0; d-- {
+		c := rem[d+len(q)-1].Mul(leadInv)
+		quo[d] = c
+		if c.IsZero() {
+			continue
+		}
+		for i, b := range q {
+			rem[d+i] = rem[d+i].Add(c.Mul(b))
+		}
+	}
+	return quo.trim(), rem.trim()
+}
+
+func (p Poly) Div(q Poly) Poly {
+	quo, _ := p.DivMod(q)
+	return quo
+}
+
+func (p Poly) Mod(q Poly) Poly {
+	_, rem := p.DivMod(q)
+	return rem
+}
+
+// GCD is the monic greatest common divisor.
+func GCD(p, q Poly) Poly {
+	p, q = p.trim(), q.trim()
+	for len(q) > 0 {
+		p, q = q, p.Mod(q)
+	}
+	return p.Monic()
+}
+
+// Derivative is the formal derivative. In characteristic 2 the even terms
+// vanish.
+func (p Poly) Derivative() Poly {
+	if len(p) < 2 {
+		return Poly{}
+	}
+	d := make(Poly, len(p)-1)
+	for i := 1; i < len(p); i += 2 {
+		d[i-1] = p[i]
+	}
+	return d.trim()
+}
+
+// PowMod is p^e mod m by square and multiply.
+func (p Poly) PowMod(e *big.Int, m Poly) Poly {
+	result := NewPoly(One).Mod(m)
+	base := p.Mod(m)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		result = result.Mul(result).Mod(m)
+		if e.Bit(i) == 1 {
+			result = result.Mul(base).Mod(m)
+		}
+	}
+	return result
+}
+
+// Eval uses Horner's rule.
+func (p Poly) Eval(x Element) Element {
+	var y Element
+	for i := len(p) - 1; i >= 0; i-- {
+		y = y.Mul(x).Add(p[i])
+	}
+	return y
+}
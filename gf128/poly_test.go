@@ -0,0 +1,72 @@
+package gf128
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPoly(t *testing.T, degree int) Poly {
+	p := Poly(randomElements(t, degree+1))
+	for p[degree].IsZero() {
+		var err error
+		p[degree], err = Random()
+		require.NoError(t, err)
+	}
+	return p
+}
+
+func TestPolyArithmetic(t *testing.T) {
+	t.Run("Trimming", func(t *testing.T) {
+		p := NewPoly(One, Zero, Zero)
+		assert.Equal(t, 0, p.Degree())
+		assert.True(t, p.IsOne())
+		assert.Equal(t, -1, p.Add(p).Degree())
+		assert.True(t, p.Add(p).IsZero())
+	})
+
+	t.Run("DivMod", func(t *testing.T) {
+		p, q := randomPoly(t, 9), randomPoly(t, 4)
+		quo, rem := p.DivMod(q)
+		assert.Equal(t, 5, quo.Degree())
+		assert.Less(t, rem.Degree(), 4)
+		assert.True(t, quo.Mul(q).Add(rem).Equal(p))
+
+		quo, rem = q.DivMod(p)
+		assert.True(t, quo.IsZero())
+		assert.True(t, rem.Equal(q))
+
+		assert.Panics(t, func() { p.DivMod(Poly{}) })
+	})
+
+	t.Run("GCD", func(t *testing.T) {
+		a, b, c := randomPoly(t, 3), randomPoly(t, 4), randomPoly(t, 5)
+		assert.True(t, GCD(a.Mul(b), a.Mul(c)).Equal(a.Monic()))
+		assert.True(t, GCD(a, Poly{}).Equal(a.Monic()))
+	})
+
+	t.Run("Eval respects multiplication", func(t *testing.T) {
+		a, b := randomPoly(t, 3), randomPoly(t, 4)
+		x := randomElements(t, 1)[0]
+		assert.Equal(t, a.Eval(x).Mul(b.Eval(x)), a.Mul(b).Eval(x))
+	})
+
+	t.Run("Derivative", func(t *testing.T) {
+		// (a + bx + cx^2 + dx^3)' = b + 3cx... = b + dx^2 in characteristic 2
+		p := randomPoly(t, 3)
+		assert.True(t, NewPoly(p[1], Zero, p[3]).Equal(p.Derivative()))
+		a, b := randomPoly(t, 3), randomPoly(t, 2)
+		assert.True(t, a.Mul(b).Derivative().Equal(a.Derivative().Mul(b).Add(a.Mul(b.Derivative()))))
+	})
+
+	t.Run("PowMod", func(t *testing.T) {
+		p, m := randomPoly(t, 3), randomPoly(t, 5)
+		expected := NewPoly(One)
+		for i := 0; i < 13; i++ {
+			expected = expected.Mul(p).Mod(m)
+		}
+		assert.True(t, expected.Equal(p.PowMod(big.NewInt(13), m)))
+	})
+}
@@ -0,0 +1,82 @@
+package lattice
+
+import (
+	"errors"
+	"math/big"
+)
+
+// HNP is an instance of the hidden number problem: find x mod Q given pairs
+// T[i], U[i] where T[i]*x + U[i] mod Q, taken in (-Q/2, Q/2], is at most
+// Q/2^(L+1) in absolute value. Each pair leaks about L bits of x.
+type HNP struct {
+	Q    *big.Int
+	T, U []*big.Int
+	L    uint
+}
+
+// Basis is the lattice spanned by the rows
+//
+//	Q 0 ... 0  0  0
+//	0 Q ... 0  0  0
+//	    ...
+//	t1 t2 ... tn ct 0
+//	u1 u2 ... un 0  cu
+//
+// with ct = 1/2^(L+1) and cu = Q/2^(L+1). x*T + U minus the right multiples
+// of Q is a lattice vector whose entries are all at most cu, which is short
+// enough for LLL to find.
+func (h *HNP) Basis() []Vector {
+	n := len(h.T)
+	scale := new(big.Int).Lsh(big.NewInt(1), h.L+1)
+	basis := make([]Vector, n+2)
+
+	for i := 0; i < n; i++ {
+		basis[i] = NewVector(n + 2)
+		basis[i][i].SetInt(h.Q)
+	}
+
+	basis[n] = NewVector(n + 2)
+	basis[n+1] = NewVector(n + 2)
+	for i := 0; i < n; i++ {
+		basis[n][i].SetInt(h.T[i])
+		basis[n+1][i].SetInt(h.U[i])
+	}
+	basis[n][n].SetFrac(big.NewInt(1), scale)
+	basis[n+1][n+1].SetFrac(h.Q, scale)
+
+	return basis
+}
+
+// Solve reduces the basis and returns every candidate x from rows that end
+// in ±cu. The caller has to check which, if any, is right.
+func (h *HNP) Solve() ([]*big.Int, error) {
+	if len(h.T) != len(h.U) || len(h.T) == 0 {
+		return nil, errors.New("lattice: need the same number of t and u values")
+	}
+
+	n := len(h.T)
+	scale := new(big.Int).Lsh(big.NewInt(1), h.L+1)
+	cu := new(big.Rat).SetFrac(h.Q, scale)
+	negCu := new(big.Rat).Neg(cu)
+	ctInv := new(big.Rat).SetInt(scale)
+
+	candidates := []*big.Int{}
+	for _, row := range LLL(h.Basis(), DefaultDelta) {
+		var x *big.Rat
+		switch {
+		case row[n+1].Cmp(cu) == 0:
+			x = new(big.Rat).Mul(row[n], ctInv)
+		case row[n+1].Cmp(negCu) == 0:
+			x = new(big.Rat).Mul(row[n], ctInv)
+			x.Neg(x)
+		default:
+			continue
+		}
+		if !x.IsInt() {
+			continue
+		}
+		candidates = append(candidates, new(big.Int).Mod(x.Num(), h.Q))
+	}
+
+	return candidates, nil
+}
@@ -0,0 +1,39 @@
+package lattice
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHNP(t *testing.T) {
+	q, err := rand.Prime(rand.Reader, 128)
+	require.NoError(t, err)
+	x, err := rand.Int(rand.Reader, q)
+	require.NoError(t, err)
+
+	// Make t*x + u land in [-q/2^9, q/2^9] by choosing the small value first
+	const l = 8
+	bound := new(big.Int).Rsh(q, l+1)
+	h := &HNP{Q: q, L: l}
+	for i := 0; i < 24; i++ {
+		ti, err := rand.Int(rand.Reader, q)
+		require.NoError(t, err)
+		small, err := rand.Int(rand.Reader, new(big.Int).Lsh(bound, 1))
+		require.NoError(t, err)
+		small.Sub(small, bound)
+
+		ui := new(big.Int).Mul(ti, x)
+		ui.Sub(small, ui)
+		ui.Mod(ui, q)
+		h.T = append(h.T, ti)
+		h.U = append(h.U, ui)
+	}
+
+	candidates, err := h.Solve()
+	require.NoError(t, err)
+	assert.Contains(t, candidates, x)
+}
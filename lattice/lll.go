@@ -0,0 +1,135 @@
+package lattice
+
+import "math/big"
+
+// Vector is a row of a lattice basis, kept exact so that reduction never
+// loses precision to rounding.
+type Vector []*big.Rat
+
+func NewVector(n int) Vector {
+	v := make(Vector, n)
+	for i := range v {
+		v[i] = new(big.Rat)
+	}
+	return v
+}
+
+func (v Vector) Clone() Vector {
+	c := make(Vector, len(v))
+	for i, x := range v {
+		c[i] = new(big.Rat).Set(x)
+	}
+	return c
+}
+
+func Dot(a, b Vector) *big.Rat {
+	sum, t := new(big.Rat), new(big.Rat)
+	for i := range a {
+		sum.Add(sum, t.Mul(a[i], b[i]))
+	}
+	return sum
+}
+
+// subScaled sets v = v - q*w.
+func (v Vector) subScaled(w Vector, q *big.Rat) {
+	t := new(big.Rat)
+	for i := range v {
+		v[i].Sub(v[i], t.Mul(q, w[i]))
+	}
+}
+
+// round returns the nearest integer to x, rounding halves up.
+func round(x *big.Rat) *big.Rat {
+	n := new(big.Int).Mul(x.Num(), big.NewInt(2))
+	n.Add(n, x.Denom())
+	d := new(big.Int).Mul(x.Denom(), big.NewInt(2))
+	// Floor division, since Num can be negative
+	n.Div(n, d)
+	return new(big.Rat).SetInt(n)
+}
+
+// DefaultDelta is the usual Lovász constant of 3/4.
+var DefaultDelta = big.NewRat(3, 4)
+
+// LLL returns a delta-reduced copy of basis, whose rows must be linearly
+// independent. Only the Gram-Schmidt coefficients mu and squared lengths B
+// are kept, and both are updated in place on each size reduction and swap
+// rather than recomputing the orthogonalization.
+func LLL(basis []Vector, delta *big.Rat) []Vector {
+	n := len(basis)
+	b := make([]Vector, n)
+	for i, v := range basis {
+		b[i] = v.Clone()
+	}
+	if n < 2 {
+		return b
+	}
+
+	mu := make([][]*big.Rat, n)
+	bStar := make([]Vector, n)
+	B := make([]*big.Rat, n)
+	for i := range b {
+		mu[i] = make([]*big.Rat, n)
+		bStar[i] = b[i].Clone()
+		for j := 0; j < i; j++ {
+			mu[i][j] = new(big.Rat).Quo(Dot(b[i], bStar[j]), B[j])
+			bStar[i].subScaled(bStar[j], mu[i][j])
+		}
+		B[i] = Dot(bStar[i], bStar[i])
+	}
+
+	t := new(big.Rat)
+	for k := 1; k < n; {
+		for j := k - 1; j >= 0; j-- {
+			q := round(mu[k][j])
+			if q.Sign() == 0 {
+				continue
+			}
+			b[k].subScaled(b[j], q)
+			for l := 0; l < j; l++ {
+				mu[k][l].Sub(mu[k][l], t.Mul(q, mu[j][l]))
+			}
+			mu[k][j].Sub(mu[k][j], q)
+		}
+
+		// Lovász condition: B_k >= (delta - mu_{k,k-1}^2) B_{k-1}
+		m := mu[k][k-1]
+		bound := new(big.Rat).Mul(m, m)
+		bound.Sub(delta, bound)
+		bound.Mul(bound, B[k-1])
+		if B[k].Cmp(bound) >= 0 {
+			k++
+			continue
+		}
+
+		b[k], b[k-1] = b[k-1], b[k]
+		for j := 0; j < k-1; j++ {
+			mu[k][j], mu[k-1][j] = mu[k-1][j], mu[k][j]
+		}
+
+		m = new(big.Rat).Set(m)
+		newB := new(big.Rat).Mul(m, m)
+		newB.Mul(newB, B[k-1])
+		newB.Add(newB, B[k])
+
+		mu[k][k-1] = new(big.Rat).Mul(m, B[k-1])
+		mu[k][k-1].Quo(mu[k][k-1], newB)
+		B[k] = new(big.Rat).Mul(B[k-1], B[k])
+		B[k].Quo(B[k], newB)
+		B[k-1] = newB
+
+		for i := k + 1; i < n; i++ {
+			old := mu[i][k]
+			mu[i][k] = new(big.Rat).Mul(m, old)
+			mu[i][k].Sub(mu[i][k-1], mu[i][k])
+			mu[i][k-1] = new(big.Rat).Mul(mu[k][k-1], mu[i][k])
+			mu[i][k-1].Add(mu[i][k-1], old)
+		}
+
+		if k > 1 {
+			k--
+		}
+	}
+
+	return b
+}
@@ -0,0 +1,106 @@
+package lattice
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intBasis(rows [][]int64) []Vector {
+	basis := make([]Vector, len(rows))
+	for i, row := range rows {
+		basis[i] = NewVector(len(row))
+		for j, x := range row {
+			basis[i][j].SetInt64(x)
+		}
+	}
+	return basis
+}
+
+// rows renders a basis with RatString so bases compare by value.
+func rows(b []Vector) [][]string {
+	out := make([][]string, len(b))
+	for i, v := range b {
+		for _, x := range v {
+			out[i] = append(out[i], x.RatString())
+		}
+	}
+	return out
+}
+
+// gramSchmidt recomputes mu and the squared lengths B from scratch.
+func gramSchmidt(b []Vector) ([][]*big.Rat, []*big.Rat) {
+	bStar := make([]Vector, len(b))
+	mu := make([][]*big.Rat, len(b))
+	B := make([]*big.Rat, len(b))
+	for i := range b {
+		bStar[i] = b[i].Clone()
+		mu[i] = make([]*big.Rat, i)
+		for j := 0; j < i; j++ {
+			mu[i][j] = new(big.Rat).Quo(Dot(b[i], bStar[j]), B[j])
+			bStar[i].subScaled(bStar[j], mu[i][j])
+		}
+		B[i] = Dot(bStar[i], bStar[i])
+	}
+	return mu, B
+}
+
+// volume is the squared volume of the lattice, which reduction preserves.
+func volume(b []Vector) string {
+	_, B := gramSchmidt(b)
+	v := big.NewRat(1, 1)
+	for _, x := range B {
+		v.Mul(v, x)
+	}
+	return v.RatString()
+}
+
+func assertReduced(t *testing.T, b []Vector, delta *big.Rat) {
+	mu, B := gramSchmidt(b)
+	half := big.NewRat(1, 2)
+	for i := range b {
+		for j := 0; j < i; j++ {
+			assert.True(t, new(big.Rat).Abs(mu[i][j]).Cmp(half) <= 0, "mu[%d][%d] = %s", i, j, mu[i][j])
+		}
+		if i > 0 {
+			bound := new(big.Rat).Mul(mu[i][i-1], mu[i][i-1])
+			bound.Sub(delta, bound)
+			bound.Mul(bound, B[i-1])
+			assert.True(t, B[i].Cmp(bound) >= 0, "Lovász condition fails at %d", i)
+		}
+	}
+}
+
+func TestLLL(t *testing.T) {
+	t.Run("Known example", func(t *testing.T) {
+		basis := intBasis([][]int64{{1, 1, 1}, {-1, 0, 2}, {3, 5, 6}})
+		reduced := LLL(basis, DefaultDelta)
+		assertReduced(t, reduced, DefaultDelta)
+		assert.Equal(t, []string{"0", "1", "0"}, rows(reduced)[0])
+		assert.Equal(t, volume(basis), volume(reduced))
+
+		// The input is left alone
+		assert.Equal(t, [][]string{{"1", "1", "1"}, {"-1", "0", "2"}, {"3", "5", "6"}}, rows(basis))
+	})
+
+	t.Run("Random rational bases", func(t *testing.T) {
+		delta := big.NewRat(99, 100)
+		for n := 2; n <= 8; n++ {
+			basis := make([]Vector, n)
+			for i := range basis {
+				basis[i] = NewVector(n)
+				for j := range basis[i] {
+					x, err := rand.Int(rand.Reader, big.NewInt(1<<20))
+					require.NoError(t, err)
+					basis[i][j].SetFrac(x, big.NewInt(int64(j+1)))
+				}
+			}
+			reduced := LLL(basis, delta)
+			assertReduced(t, reduced, delta)
+			assert.Equal(t, volume(basis), volume(reduced))
+		}
+	})
+}
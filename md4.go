@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+var MD4IV = [4]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476}
+
+const (
+	md4Round2K = 0x5a827999
+	md4Round3K = 0x6ed9eba1
+)
+
+var (
+	md4Round1Shifts = [4]int{3, 7, 11, 19}
+	md4Round2Shifts = [4]int{3, 5, 9, 13}
+	md4Round3Shifts = [4]int{3, 9, 11, 15}
+	md4Round1Order  = [16]int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	md4Round2Order  = [16]int{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+	md4Round3Order  = [16]int{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+)
+
+func md4F(x, y, z uint32) uint32 { return (x & y) | (^x & z) }
+func md4G(x, y, z uint32) uint32 { return (x & y) | (x & z) | (y & z) }
+func md4H(x, y, z uint32) uint32 { return x ^ y ^ z }
+
+// MD4Round1Step is a = (a + F(b, c, d) + m) <<< s. The other rounds follow
+// the same pattern with their own function and constant.
+func MD4Round1Step(a, b, c, d, m uint32, s int) uint32 {
+	return bits.RotateLeft32(a+md4F(b, c, d)+m, s)
+}
+
+func MD4Round2Step(a, b, c, d, m uint32, s int) uint32 {
+	return bits.RotateLeft32(a+md4G(b, c, d)+m+md4Round2K, s)
+}
+
+func MD4Round3Step(a, b, c, d, m uint32, s int) uint32 {
+	return bits.RotateLeft32(a+md4H(b, c, d)+m+md4Round3K, s)
+}
+
+// md4Round applies the 16 steps of one round. The registers rotate through
+// a, d, c, b as each step updates one of them.
+func md4Round(state [4]uint32, x *[16]uint32, order *[16]int, shifts *[4]int, step func(a, b, c, d, m uint32, s int) uint32) [4]uint32 {
+	a, b, c, d := state[0], state[1], state[2], state[3]
+	for i := 0; i < 16; i += 4 {
+		a = step(a, b, c, d, x[order[i]], shifts[0])
+		d = step(d, a, b, c, x[order[i+1]], shifts[1])
+		c = step(c, d, a, b, x[order[i+2]], shifts[2])
+		b = step(b, c, d, a, x[order[i+3]], shifts[3])
+	}
+	return [4]uint32{a, b, c, d}
+}
+
+func MD4Round1(state [4]uint32, x *[16]uint32) [4]uint32 {
+	return md4Round(state, x, &md4Round1Order, &md4Round1Shifts, MD4Round1Step)
+}
+
+func MD4Round2(state [4]uint32, x *[16]uint32) [4]uint32 {
+	return md4Round(state, x, &md4Round2Order, &md4Round2Shifts, MD4Round2Step)
+}
+
+func MD4Round3(state [4]uint32, x *[16]uint32) [4]uint32 {
+	return md4Round(state, x, &md4Round3Order, &md4Round3Shifts, MD4Round3Step)
+}
+
+func MD4BlockWords(block []byte) [16]uint32 {
+	x := [16]uint32{}
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(block[4*i:])
+	}
+	return x
+}
+
+func MD4WordsBlock(x [16]uint32) []byte {
+	block := make([]byte, 64)
+	for i, w := range x {
+		binary.LittleEndian.PutUint32(block[4*i:], w)
+	}
+	return block
+}
+
+func MD4Compress(state [4]uint32, block []byte) [4]uint32 {
+	x := MD4BlockWords(block)
+	out := MD4Round3(MD4Round2(MD4Round1(state, &x), &x), &x)
+	for i := range out {
+		out[i] += state[i]
+	}
+	return out
+}
+
+func MD4Pad(msg []byte) []byte {
+	padded := append([]byte{}, msg...)
+	padded = append(padded, 0x80)
+	for len(padded)%64 != 56 {
+		padded = append(padded, 0)
+	}
+	return binary.LittleEndian.AppendUint64(padded, uint64(len(msg))*8)
+}
+
+func MD4Sum(msg []byte) [16]byte {
+	state := MD4IV
+	padded := MD4Pad(msg)
+	for i := 0; i < len(padded); i += 64 {
+		state = MD4Compress(state, padded[i:i+64])
+	}
+
+	digest := [16]byte{}
+	for i, w := range state {
+		binary.LittleEndian.PutUint32(digest[4*i:], w)
+	}
+	return digest
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMD4(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{"", "31d6cfe0d16ae931b73c59d7e0c089c0"},
+		{"a", "bde52cb31de33e46245e05fbdbd6fb24"},
+		{"abc", "a448017aaf21d8525fc10ae87aa6729d"},
+		{"message digest", "d9130a8164549fe818874806e1c7014b"},
+		{"abcdefghijklmnopqrstuvwxyz", "d79e1c308aa5bbcdeea8ed63df412da9"},
+		{"12345678901234567890123456789012345678901234567890123456789012345678901234567890", "e33b4ddc9c38f2199c3e7b164fcc0536"},
+	}
+	for _, tc := range cases {
+		digest := MD4Sum([]byte(tc.input))
+		assert.Equal(t, tc.expected, hex.EncodeToString(digest[:]), tc.input)
+	}
+}
+
+func TestMD4Words(t *testing.T) {
+	block := RandomBytes(64)
+	assert.Equal(t, block, MD4WordsBlock(MD4BlockWords(block)))
+}
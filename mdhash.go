@@ -0,0 +1,434 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const mdBlockSize = 16
+
+// MDHash is a toy Merkle-Damgard hash. The compression function encrypts the
+// message block with AES using the state (zero padded) as the key and keeps
+// the first Size bytes, so collisions are cheap enough to find by brute force.
+type MDHash struct {
+	Size int
+	IV   []byte
+
+	// Calls counts compression function calls, for comparing attack costs
+	Calls int
+}
+
+func NewMDHash(size int) *MDHash {
+	if size < 1 || size > 16 {
+		panic("MDHash size must be between 1 and 16 bytes")
+	}
+	iv := make([]byte, size)
+	for i := range iv {
+		iv[i] = byte(0xa5 + 0x13*i)
+	}
+	return &MDHash{Size: size, IV: iv}
+}
+
+func (h *MDHash) Compress(state, block []byte) []byte {
+	h.Calls++
+	key := make([]byte, 16)
+	copy(key, state)
+	// Cap the block so EncryptECB's padding can't overwrite the next one
+	return EncryptECB(block[:mdBlockSize:mdBlockSize], key)[:h.Size]
+}
+
+// HashBlocks runs the compression function over whole blocks starting from
+// state, without any padding.
+func (h *MDHash) HashBlocks(state, msg []byte) []byte {
+	if len(msg)%mdBlockSize != 0 {
+		panic("Message is not a whole number of blocks")
+	}
+	for i := 0; i < len(msg); i += mdBlockSize {
+		state = h.Compress(state, msg[i:i+mdBlockSize])
+	}
+	return state
+}
+
+// MDPad appends 0x80, zeroes and the message length in bits as a 64 bit big
+// endian integer, filling out the last block.
+func MDPad(msg []byte, totalLen int) []byte {
+	padded := append([]byte{}, msg...)
+	padded = append(padded, 0x80)
+	for len(padded)%mdBlockSize != mdBlockSize-8 {
+		padded = append(padded, 0)
+	}
+	return binary.BigEndian.AppendUint64(padded, uint64(totalLen)*8)
+}
+
+func (h *MDHash) Sum(msg []byte) []byte {
+	return h.HashBlocks(h.IV, MDPad(msg, len(msg)))
+}
+
+// FindCollision searches random blocks until two of them compress to the same
+// value from state. It returns both blocks and the shared output.
+func (h *MDHash) FindCollision(state []byte) ([]byte, []byte, []byte) {
+	seen := make(map[string][]byte)
+	for {
+		block := RandomBytes(mdBlockSize)
+		out := h.Compress(state, block)
+		if prev, exists := seen[string(out)]; exists && !bytes.Equal(prev, block) {
+			return prev, block, out
+		}
+		seen[string(out)] = block
+	}
+}
+
+// Multicollision is a chain of colliding block pairs. Choosing either block
+// from each pair gives 2^len(Pairs) messages that all reach State.
+type Multicollision struct {
+	Pairs [][2][]byte
+	State []byte
+}
+
+func (h *MDHash) MultiCollide(n int) *Multicollision {
+	mc := &Multicollision{State: h.IV}
+	h.Extend(mc, n)
+	return mc
+}
+
+// Extend adds n more collisions to the end of mc, doubling the number of
+// messages each time.
+func (h *MDHash) Extend(mc *Multicollision, n int) {
+	for i := 0; i < n; i++ {
+		a, b, out := h.FindCollision(mc.State)
+		mc.Pairs = append(mc.Pairs, [2][]byte{a, b})
+		mc.State = out
+	}
+}
+
+func (mc *Multicollision) Messages() [][]byte {
+	msgs := [][]byte{{}}
+	for _, pair := range mc.Pairs {
+		next := make([][]byte, 0, 2*len(msgs))
+		for _, m := range msgs {
+			for _, block := range pair {
+				next = append(next, append(append([]byte{}, m...), block...))
+			}
+		}
+		msgs = next
+	}
+	return msgs
+}
+
+type CascadeStats struct {
+	FCollisions int
+	FCalls      int
+	GCalls      int
+}
+
+// CascadeCollision finds two messages that collide under f(m) || g(m), where
+// f is the cheaper hash. Every message in an f multicollision collides in f,
+// so it's enough to keep extending one until two of its messages collide in
+// g, which takes about 2^(g.Size*4) messages.
+func CascadeCollision(f, g *MDHash) ([]byte, []byte, CascadeStats) {
+	fStart, gStart := f.Calls, g.Calls
+	mc := f.MultiCollide(g.Size * 4)
+
+	for {
+		// Walk the tree of messages so shared prefixes are only hashed once
+		seen := make(map[string][]byte)
+		var a, b []byte
+		var walk func(depth int, state, prefix []byte) bool
+		walk = func(depth int, state, prefix []byte) bool {
+			if depth == len(mc.Pairs) {
+				// Collisions are all the same length so the padding block
+				// is the same too, and the states can be compared directly
+				if prev, exists := seen[string(state)]; exists {
+					a, b = prev, prefix
+					return true
+				}
+				seen[string(state)] = prefix
+				return false
+			}
+			for _, block := range mc.Pairs[depth] {
+				next := g.Compress(state, block)
+				msg := append(append([]byte{}, prefix...), block...)
+				if walk(depth+1, next, msg) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if walk(0, g.IV, []byte{}) {
+			return a, b, CascadeStats{
+				FCollisions: len(mc.Pairs),
+				FCalls:      f.Calls - fStart,
+				GCalls:      g.Calls - gStart,
+			}
+		}
+		f.Extend(mc, 1)
+	}
+}
+
+// findCrossCollision finds a block a from stateA and a block b from stateB
+// that compress to the same value.
+func (h *MDHash) findCrossCollision(stateA, stateB []byte) ([]byte, []byte, []byte) {
+	fromA := make(map[string][]byte)
+	fromB := make(map[string][]byte)
+	for {
+		a := RandomBytes(mdBlockSize)
+		outA := h.Compress(stateA, a)
+		if b, exists := fromB[string(outA)]; exists {
+			return a, b, outA
+		}
+		fromA[string(outA)] = a
+
+		b := RandomBytes(mdBlockSize)
+		outB := h.Compress(stateB, b)
+		if a, exists := fromA[string(outB)]; exists {
+			return a, b, outB
+		}
+		fromB[string(outB)] = b
+	}
+}
+
+// ExpandableMessage holds k pairs of colliding messages where the first of
+// each pair is one block and the second is 2^i + 1 blocks. Picking one from
+// each pair gives a message of any length from k to k + 2^k - 1 blocks that
+// always ends in State.
+type ExpandableMessage struct {
+	K     int
+	Pairs [][2][]byte
+	State []byte
+}
+
+func (h *MDHash) NewExpandableMessage(k int) *ExpandableMessage {
+	em := &ExpandableMessage{K: k, State: h.IV}
+	for i := k - 1; i >= 0; i-- {
+		dummy := make([]byte, (1<<i)*mdBlockSize)
+		dummyState := h.HashBlocks(em.State, dummy)
+		short, last, out := h.findCrossCollision(em.State, dummyState)
+		em.Pairs = append(em.Pairs, [2][]byte{short, append(dummy, last...)})
+		em.State = out
+	}
+	return em
+}
+
+// Produce returns a message of exactly blocks blocks.
+func (em *ExpandableMessage) Produce(blocks int) ([]byte, error) {
+	extra := blocks - em.K
+	if extra < 0 || extra >= 1<<em.K {
+		return nil, fmt.Errorf("expandable message can't be %d blocks long", blocks)
+	}
+
+	msg := []byte{}
+	for idx, pair := range em.Pairs {
+		i := em.K - 1 - idx
+		if extra&(1<<i) != 0 {
+			msg = append(msg, pair[1]...)
+		} else {
+			msg = append(msg, pair[0]...)
+		}
+	}
+	return msg, nil
+}
+
+// SecondPreimage finds a different message with the same length and hash as
+// msg, which should be around 2^k blocks long. It finds a bridge block from
+// the end of an expandable message to one of msg's intermediate states, then
+// expands the prefix so the forgery has the same length as msg and the
+// length padding matches too.
+func (h *MDHash) SecondPreimage(msg []byte, k int) ([]byte, error) {
+	numBlocks := len(msg) / mdBlockSize
+	if numBlocks <= k+1 {
+		return nil, errors.New("message too short for expandable message")
+	}
+
+	// Map each reachable intermediate state to the number of blocks before it
+	states := make(map[string]int)
+	state := h.IV
+	for j := 1; j <= numBlocks; j++ {
+		state = h.Compress(state, msg[(j-1)*mdBlockSize:j*mdBlockSize])
+		prefixLen := j - 1
+		if prefixLen >= k && prefixLen < k+(1<<k) {
+			states[string(state)] = j
+		}
+	}
+
+	em := h.NewExpandableMessage(k)
+	for {
+		bridge := RandomBytes(mdBlockSize)
+		j, exists := states[string(h.Compress(em.State, bridge))]
+		if !exists {
+			continue
+		}
+
+		prefix, err := em.Produce(j - 1)
+		if err != nil {
+			return nil, err
+		}
+		forged := append(prefix, bridge...)
+		return append(forged, msg[j*mdBlockSize:]...), nil
+	}
+}
+
+// Diamond is a funnel of collisions for the herding attack. Each of the 2^K
+// leaf states is paired up and collided into the next level, so every leaf
+// has a K block path to Root. The hash it commits to assumes a prefix of
+// PrefixBlocks blocks, a linking block and then the path.
+type Diamond struct {
+	HashSize     int
+	IV           []byte
+	K            int
+	PrefixBlocks int
+	Leaves       [][]byte
+	// Blocks[level][i] takes node i at that level to node i/2 at the next
+	Blocks [][][]byte
+	Root   []byte
+}
+
+func (d *Diamond) hash() *MDHash {
+	return &MDHash{Size: d.HashSize, IV: d.IV}
+}
+
+func BuildDiamond(h *MDHash, k, prefixBlocks int) *Diamond {
+	d := &Diamond{
+		HashSize:     h.Size,
+		IV:           h.IV,
+		K:            k,
+		PrefixBlocks: prefixBlocks,
+	}
+
+	// Leaves can be any state at all, since the link block only has to hit
+	// one of them
+	level := make([][]byte, 1<<k)
+	for i := range level {
+		level[i] = RandomBytes(h.Size)
+	}
+	d.Leaves = level
+
+	for l := 0; l < k; l++ {
+		blocks := make([][]byte, len(level))
+		next := make([][]byte, len(level)/2)
+		for i := 0; i < len(level); i += 2 {
+			a, b, out := h.findCrossCollision(level[i], level[i+1])
+			blocks[i], blocks[i+1] = a, b
+			next[i/2] = out
+		}
+		d.Blocks = append(d.Blocks, blocks)
+		level = next
+	}
+	d.Root = level[0]
+
+	return d
+}
+
+func (d *Diamond) messageLen() int {
+	return (d.PrefixBlocks + 1 + d.K) * mdBlockSize
+}
+
+// Prediction is the hash to publish before the prefix is known.
+func (d *Diamond) Prediction() []byte {
+	return d.hash().HashBlocks(d.Root, MDPad(nil, d.messageLen()))
+}
+
+// Herd returns a message that starts with prefix and hashes to the
+// prediction. The prefix is padded with spaces to PrefixBlocks blocks.
+func (d *Diamond) Herd(prefix []byte) ([]byte, error) {
+	size := d.PrefixBlocks * mdBlockSize
+	if len(prefix) > size {
+		return nil, fmt.Errorf("prefix must fit in %d blocks", d.PrefixBlocks)
+	}
+	msg := append([]byte{}, prefix...)
+	msg = append(msg, bytes.Repeat([]byte{' '}, size-len(msg))...)
+
+	h := d.hash()
+	leaves := make(map[string]int)
+	for i, leaf := range d.Leaves {
+		leaves[string(leaf)] = i
+	}
+
+	state := h.HashBlocks(h.IV, msg)
+	for {
+		link := RandomBytes(mdBlockSize)
+		i, exists := leaves[string(h.Compress(state, link))]
+		if !exists {
+			continue
+		}
+
+		msg = append(msg, link...)
+		for _, blocks := range d.Blocks {
+			msg = append(msg, blocks[i]...)
+			i /= 2
+		}
+		return msg, nil
+	}
+}
+
+func (d *Diamond) Save(w io.Writer) error {
+	return gob.NewEncoder(w).Encode(d)
+}
+
+func LoadDiamond(r io.Reader) (*Diamond, error) {
+	d := &Diamond{}
+	if err := gob.NewDecoder(r).Decode(d); err != nil {
+		return nil, err
+	}
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// validate checks every length Herd and Prediction rely on, since a loaded
+// diamond could be anything.
+func (d *Diamond) validate() error {
+	malformed := errors.New("diamond structure is malformed")
+	if d.HashSize < 1 || d.HashSize > 16 || len(d.IV) != d.HashSize || len(d.Root) != d.HashSize {
+		return malformed
+	}
+	if d.K < 0 || d.K > 30 || d.PrefixBlocks < 0 {
+		return malformed
+	}
+	if len(d.Leaves) != 1<<d.K || len(d.Blocks) != d.K {
+		return malformed
+	}
+	for _, leaf := range d.Leaves {
+		if len(leaf) != d.HashSize {
+			return malformed
+		}
+	}
+	for l, blocks := range d.Blocks {
+		if len(blocks) != 1<<(d.K-l) {
+			return malformed
+		}
+		for _, block := range blocks {
+			if len(block) != mdBlockSize {
+				return malformed
+			}
+		}
+	}
+	return nil
+}
+
+func (d *Diamond) SaveFile(filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := d.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func LoadDiamondFile(filename string) (*Diamond, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return LoadDiamond(file)
+}
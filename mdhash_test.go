@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMDHash(t *testing.T) {
+	t.Run("Sum has configured size", func(t *testing.T) {
+		for _, size := range []int{2, 3, 4} {
+			h := NewMDHash(size)
+			assert.Len(t, h.Sum([]byte("hello")), size)
+		}
+	})
+
+	t.Run("Padding fills a block and encodes length", func(t *testing.T) {
+		padded := MDPad([]byte("abc"), 3)
+		assert.Len(t, padded, 16)
+		assert.Equal(t, byte(0x80), padded[3])
+		assert.Equal(t, byte(24), padded[15])
+		assert.Len(t, MDPad(bytes.Repeat([]byte{'a'}, 9), 9), 32)
+	})
+
+	t.Run("Different messages usually differ", func(t *testing.T) {
+		h := NewMDHash(4)
+		assert.NotEqual(t, h.Sum([]byte("hello")), h.Sum([]byte("hellp")))
+		assert.Equal(t, h.Sum([]byte("hello")), h.Sum([]byte("hello")))
+	})
+}
+
+func TestMultiCollide(t *testing.T) {
+	h := NewMDHash(2)
+	mc := h.MultiCollide(5)
+	msgs := mc.Messages()
+	assert.Len(t, msgs, 32)
+
+	want := h.Sum(msgs[0])
+	unique := make(map[string]struct{})
+	for _, m := range msgs {
+		assert.Equal(t, want, h.Sum(m))
+		unique[string(m)] = struct{}{}
+	}
+	assert.Len(t, unique, 32)
+}
+
+func TestCascadeCollision(t *testing.T) {
+	for _, gSize := range []int{3, 4} {
+		f, g := NewMDHash(2), NewMDHash(gSize)
+		a, b, stats := CascadeCollision(f, g)
+
+		assert.NotEqual(t, a, b)
+		assert.Equal(t, f.Sum(a), f.Sum(b))
+		assert.Equal(t, g.Sum(a), g.Sum(b))
+		t.Logf("g size %d: %d f collisions, %d f calls, %d g calls", gSize, stats.FCollisions, stats.FCalls, stats.GCalls)
+	}
+}
+
+func BenchmarkCascadeCollision(b *testing.B) {
+	fCalls, gCalls := 0, 0
+	for i := 0; i < b.N; i++ {
+		_, _, stats := CascadeCollision(NewMDHash(2), NewMDHash(3))
+		fCalls += stats.FCalls
+		gCalls += stats.GCalls
+	}
+	b.ReportMetric(float64(fCalls)/float64(b.N), "f-calls/op")
+	b.ReportMetric(float64(gCalls)/float64(b.N), "g-calls/op")
+}
+
+func TestExpandableMessage(t *testing.T) {
+	h := NewMDHash(2)
+	k := 4
+	em := h.NewExpandableMessage(k)
+
+	for blocks := k; blocks < k+(1<<k); blocks++ {
+		msg, err := em.Produce(blocks)
+		require.NoError(t, err)
+		assert.Len(t, msg, blocks*mdBlockSize)
+		assert.Equal(t, em.State, h.HashBlocks(h.IV, msg))
+	}
+
+	_, err := em.Produce(k - 1)
+	assert.Error(t, err)
+	_, err = em.Produce(k + (1 << k))
+	assert.Error(t, err)
+}
+
+func TestSecondPreimage(t *testing.T) {
+	h := NewMDHash(3)
+	k := 10
+	msg := RandomBytes((1<<k)*mdBlockSize + 5)
+
+	forged, err := h.SecondPreimage(msg, k)
+	require.NoError(t, err)
+	assert.NotEqual(t, msg, forged)
+	assert.Equal(t, len(msg), len(forged))
+	assert.Equal(t, h.Sum(msg), h.Sum(forged))
+}
+
+func TestHerding(t *testing.T) {
+	h := NewMDHash(3)
+	d := BuildDiamond(h, 6, 3)
+	prediction := d.Prediction()
+
+	t.Run("Every leaf reaches the root", func(t *testing.T) {
+		for i, leaf := range d.Leaves {
+			state := leaf
+			for _, blocks := range d.Blocks {
+				state = h.Compress(state, blocks[i])
+				i /= 2
+			}
+			assert.Equal(t, d.Root, state)
+		}
+	})
+
+	t.Run("Herd message to prediction", func(t *testing.T) {
+		prefix := []byte("Final score: Red Sox 4, Yankees 2")
+		msg, err := d.Herd(prefix)
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(msg, prefix))
+		assert.Equal(t, prediction, h.Sum(msg))
+	})
+
+	t.Run("Prefix too long", func(t *testing.T) {
+		_, err := d.Herd(bytes.Repeat([]byte{'a'}, 49))
+		assert.Error(t, err)
+	})
+
+	t.Run("Reuse diamond from disk", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "diamond.gob")
+		require.NoError(t, d.SaveFile(filename))
+		loaded, err := LoadDiamondFile(filename)
+		require.NoError(t, err)
+		assert.Equal(t, prediction, loaded.Prediction())
+
+		msg, err := loaded.Herd([]byte("Final score: Yankees 7, Mets 1"))
+		require.NoError(t, err)
+		assert.Equal(t, prediction, h.Sum(msg))
+	})
+
+	t.Run("Reject malformed diamonds", func(t *testing.T) {
+		corruptions := map[string]func(d *Diamond){
+			"short level":    func(d *Diamond) { d.Blocks[2] = d.Blocks[2][:3] },
+			"short block":    func(d *Diamond) { d.Blocks[0][5] = d.Blocks[0][5][:4] },
+			"short leaf":     func(d *Diamond) { d.Leaves[7] = d.Leaves[7][:1] },
+			"short root":     func(d *Diamond) { d.Root = d.Root[:2] },
+			"zero hash size": func(d *Diamond) { d.HashSize = 0 },
+			"big hash size":  func(d *Diamond) { d.HashSize = 17 },
+			"negative K":     func(d *Diamond) { d.K = -1 },
+		}
+		for name, corrupt := range corruptions {
+			var buf bytes.Buffer
+			require.NoError(t, d.Save(&buf))
+			bad, err := LoadDiamond(&buf)
+			require.NoError(t, err)
+			corrupt(bad)
+
+			buf.Reset()
+			require.NoError(t, bad.Save(&buf))
+			_, err = LoadDiamond(&buf)
+			assert.Error(t, err, name)
+		}
+	})
+}
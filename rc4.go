@@ -0,0 +1,51 @@
+package main
+
+// RC4 is the stream cipher, written out rather than using crypto/rc4 so the
+// keystream can be inspected directly.
+type RC4 struct {
+	s    [256]byte
+	i, j uint8
+}
+
+func NewRC4(key []byte) *RC4 {
+	if len(key) < 1 || len(key) > 256 {
+		panic("RC4 key must be between 1 and 256 bytes")
+	}
+
+	r := &RC4{}
+	r.schedule(key)
+	return r
+}
+
+// schedule runs the key scheduling algorithm.
+func (r *RC4) schedule(key []byte) {
+	for i := range r.s {
+		r.s[i] = byte(i)
+	}
+	j, k := uint8(0), 0
+	for i := 0; i < 256; i++ {
+		j += r.s[i] + key[k]
+		r.s[i], r.s[j] = r.s[j], r.s[i]
+		if k++; k == len(key) {
+			k = 0
+		}
+	}
+	r.i, r.j = 0, 0
+}
+
+func (r *RC4) XORKeyStream(dst, src []byte) {
+	i, j := r.i, r.j
+	for k, b := range src {
+		i++
+		j += r.s[i]
+		r.s[i], r.s[j] = r.s[j], r.s[i]
+		dst[k] = b ^ r.s[r.s[i]+r.s[j]]
+	}
+	r.i, r.j = i, j
+}
+
+func RC4Encrypt(text, key []byte) []byte {
+	out := make([]byte, len(text))
+	NewRC4(key).XORKeyStream(out, text)
+	return out
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRC4(t *testing.T) {
+	cases := []struct {
+		key      string
+		input    string
+		expected string
+	}{
+		{"Key", "Plaintext", "bbf316e8d940af0ad3"},
+		{"Wiki", "pedia", "1021bf0420"},
+		{"Secret", "Attack at dawn", "45a01f645fc35b383552544b9bf5"},
+	}
+	for _, tc := range cases {
+		cText := RC4Encrypt([]byte(tc.input), []byte(tc.key))
+		assert.Equal(t, tc.expected, hex.EncodeToString(cText))
+		assert.Equal(t, tc.input, string(RC4Encrypt(cText, []byte(tc.key))))
+	}
+}
+
+func BenchmarkRC4Sample(b *testing.B) {
+	buf := make([]byte, 32)
+	for i := 0; i < b.N; i++ {
+		NewRC4(RandomBytes(16)).XORKeyStream(buf, buf)
+	}
+}
@@ -0,0 +1,258 @@
+package rsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"sort"
+	"sync/atomic"
+)
+
+var ErrDecryption = errors.New("rsa: decryption error")
+
+// PadPKCS1v15 builds the k byte encryption block 00 02 PS 00 msg, where PS is
+// at least 8 random non-zero bytes.
+func PadPKCS1v15(msg []byte, k int) ([]byte, error) {
+	if len(msg) > k-11 {
+		return nil, errors.New("rsa: message too long")
+	}
+
+	em := make([]byte, k)
+	em[1] = 0x02
+	ps := em[2 : k-len(msg)-1]
+	if _, err := rand.Read(ps); err != nil {
+		return nil, err
+	}
+	for i := range ps {
+		for ps[i] == 0 {
+			if _, err := rand.Read(ps[i : i+1]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	copy(em[k-len(msg):], msg)
+
+	return em, nil
+}
+
+func UnpadPKCS1v15(em []byte) ([]byte, error) {
+	if len(em) < 11 || em[0] != 0x00 || em[1] != 0x02 {
+		return nil, ErrDecryption
+	}
+	for i := 2; i < len(em); i++ {
+		if em[i] == 0x00 {
+			if i < 10 {
+				return nil, ErrDecryption
+			}
+			return em[i+1:], nil
+		}
+	}
+
+	return nil, ErrDecryption
+}
+
+func EncryptPKCS1v15(pub *PublicKey, msg []byte) (*big.Int, error) {
+	k := (pub.N.BitLen() + 7) / 8
+	em, err := PadPKCS1v15(msg, k)
+	if err != nil {
+		return nil, err
+	}
+	return Encrypt(pub, new(big.Int).SetBytes(em)), nil
+}
+
+func DecryptPKCS1v15(priv *PrivateKey, c *big.Int) ([]byte, error) {
+	k := (priv.N.BitLen() + 7) / 8
+	return UnpadPKCS1v15(Decrypt(priv, c).FillBytes(make([]byte, k)))
+}
+
+type PaddingOracle interface {
+	Conforming(c *big.Int) bool
+}
+
+// PKCS1Oracle only checks that the plaintext starts with 00 02. It counts
+// every query so different attack strategies can be compared.
+type PKCS1Oracle struct {
+	priv    *PrivateKey
+	k       int
+	queries atomic.Int64
+}
+
+func NewPKCS1Oracle(priv *PrivateKey) *PKCS1Oracle {
+	return &PKCS1Oracle{priv: priv, k: (priv.N.BitLen() + 7) / 8}
+}
+
+func (o *PKCS1Oracle) Conforming(c *big.Int) bool {
+	o.queries.Add(1)
+	em := Decrypt(o.priv, c).FillBytes(make([]byte, o.k))
+	return em[0] == 0x00 && em[1] == 0x02
+}
+
+func (o *PKCS1Oracle) Queries() int64 {
+	return o.queries.Load()
+}
+
+type BleichenbacherStats struct {
+	Queries    int64
+	Iterations int
+}
+
+type interval struct {
+	a, b *big.Int
+}
+
+func ceilDiv(x, y *big.Int) *big.Int {
+	// Div rounds towards negative infinity for positive y
+	q := new(big.Int).Neg(x)
+	q.Div(q, y)
+	return q.Neg(q)
+}
+
+func floorDiv(x, y *big.Int) *big.Int {
+	return new(big.Int).Div(x, y)
+}
+
+// mergeIntervals sorts the intervals and joins any that overlap.
+func mergeIntervals(intervals []interval) []interval {
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].a.Cmp(intervals[j].a) < 0
+	})
+
+	merged := []interval{}
+	for _, iv := range intervals {
+		last := len(merged) - 1
+		if last >= 0 && iv.a.Cmp(merged[last].b) <= 0 {
+			if iv.b.Cmp(merged[last].b) > 0 {
+				merged[last].b = iv.b
+			}
+			continue
+		}
+		merged = append(merged, iv)
+	}
+
+	return merged
+}
+
+// BleichenbacherAttack decrypts c using only a PKCS#1 v1.5 conformance
+// oracle, following the steps in Bleichenbacher's 1998 paper. It returns
+// the padded plaintext block as an integer.
+func BleichenbacherAttack(pub *PublicKey, c *big.Int, oracle PaddingOracle) (*big.Int, BleichenbacherStats, error) {
+	n, e := pub.N, pub.E
+	k := (n.BitLen() + 7) / 8
+	stats := BleichenbacherStats{}
+
+	query := func(c0, s *big.Int) bool {
+		stats.Queries++
+		ci := new(big.Int).Exp(s, e, n)
+		ci.Mul(ci, c0)
+		ci.Mod(ci, n)
+		return oracle.Conforming(ci)
+	}
+
+	B := new(big.Int).Lsh(bigOne, uint(8*(k-2)))
+	B2 := new(big.Int).Mul(B, bigTwo)
+	B3 := new(big.Int).Mul(B, big.NewInt(3))
+	B3minus1 := new(big.Int).Sub(B3, bigOne)
+
+	// Step 1: blinding. Skipped when c is already conforming.
+	s0 := big.NewInt(1)
+	c0 := new(big.Int).Set(c)
+	if !query(c, s0) {
+		limit := new(big.Int).Sub(n, bigTwo)
+		for {
+			r, err := rand.Int(rand.Reader, limit)
+			if err != nil {
+				return nil, stats, err
+			}
+			s0 = r.Add(r, bigTwo)
+			if query(c, s0) {
+				break
+			}
+		}
+		c0.Exp(s0, e, n)
+		c0.Mul(c0, c)
+		c0.Mod(c0, n)
+	}
+
+	M := []interval{{a: new(big.Int).Set(B2), b: new(big.Int).Set(B3minus1)}}
+	s := new(big.Int)
+
+	for i := 1; ; i++ {
+		stats.Iterations = i
+		switch {
+		case i == 1:
+			// Step 2a: smallest s >= n/3B that conforms
+			s = ceilDiv(n, B3)
+			for !query(c0, s) {
+				s.Add(s, bigOne)
+			}
+		case len(M) > 1:
+			// Step 2b: keep searching upwards
+			s.Add(s, bigOne)
+			for !query(c0, s) {
+				s.Add(s, bigOne)
+			}
+		default:
+			// Step 2c: one interval left, search r and s together which
+			// roughly halves the interval each iteration
+			a, b := M[0].a, M[0].b
+			r := new(big.Int).Mul(b, s)
+			r.Sub(r, B2)
+			r.Mul(r, bigTwo)
+			r = ceilDiv(r, n)
+			found := false
+			for !found {
+				rn := new(big.Int).Mul(r, n)
+				lo := ceilDiv(new(big.Int).Add(B2, rn), b)
+				hi := ceilDiv(new(big.Int).Add(B3, rn), a)
+				for si := lo; si.Cmp(hi) < 0; si.Add(si, bigOne) {
+					if query(c0, si) {
+						s = si
+						found = true
+						break
+					}
+				}
+				r.Add(r, bigOne)
+			}
+		}
+
+		// Step 3: narrow the set of intervals
+		next := []interval{}
+		for _, iv := range M {
+			rLo := new(big.Int).Mul(iv.a, s)
+			rLo.Sub(rLo, B3minus1)
+			rLo = ceilDiv(rLo, n)
+			rHi := new(big.Int).Mul(iv.b, s)
+			rHi.Sub(rHi, B2)
+			rHi = floorDiv(rHi, n)
+
+			for r := rLo; r.Cmp(rHi) <= 0; r.Add(r, bigOne) {
+				rn := new(big.Int).Mul(r, n)
+				a := ceilDiv(new(big.Int).Add(B2, rn), s)
+				if a.Cmp(iv.a) < 0 {
+					a = new(big.Int).Set(iv.a)
+				}
+				b := floorDiv(new(big.Int).Add(B3minus1, rn), s)
+				if b.Cmp(iv.b) > 0 {
+					b = new(big.Int).Set(iv.b)
+				}
+				if a.Cmp(b) <= 0 {
+					next = append(next, interval{a: a, b: b})
+				}
+			}
+		}
+		if len(next) == 0 {
+			return nil, stats, errors.New("rsa: no intervals left, oracle is inconsistent")
+		}
+		M = mergeIntervals(next)
+
+		// Step 4: done when a single value remains
+		if len(M) == 1 && M[0].a.Cmp(M[0].b) == 0 {
+			s0Inv, err := InvMod(s0, n)
+			if err != nil {
+				return nil, stats, err
+			}
+			m := new(big.Int).Mul(M[0].a, s0Inv)
+			return m.Mod(m, n), stats, nil
+		}
+	}
+}
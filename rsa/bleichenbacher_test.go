@@ -0,0 +1,64 @@
+package rsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPKCS1v15Encryption(t *testing.T) {
+	priv, err := GenerateKey(512, 65537)
+	require.NoError(t, err)
+	msg := []byte("kick it, CC")
+
+	t.Run("Round trip", func(t *testing.T) {
+		c, err := EncryptPKCS1v15(&priv.PublicKey, msg)
+		require.NoError(t, err)
+		decrypted, err := DecryptPKCS1v15(priv, c)
+		require.NoError(t, err)
+		assert.Equal(t, msg, decrypted)
+	})
+
+	t.Run("Reject bad padding", func(t *testing.T) {
+		_, err := DecryptPKCS1v15(priv, Encrypt(&priv.PublicKey, big.NewInt(42)))
+		assert.ErrorIs(t, err, ErrDecryption)
+	})
+
+	t.Run("Message too long", func(t *testing.T) {
+		_, err := PadPKCS1v15(make([]byte, 60), 64)
+		assert.Error(t, err)
+	})
+}
+
+func testBleichenbacher(t *testing.T, bits int) {
+	priv, err := GenerateKey(bits, 3)
+	require.NoError(t, err)
+	oracle := NewPKCS1Oracle(priv)
+	msg := []byte("kick it, CC")
+
+	c, err := EncryptPKCS1v15(&priv.PublicKey, msg)
+	require.NoError(t, err)
+	require.True(t, oracle.Conforming(c))
+
+	m, stats, err := BleichenbacherAttack(&priv.PublicKey, c, oracle)
+	require.NoError(t, err)
+
+	k := (priv.N.BitLen() + 7) / 8
+	recovered, err := UnpadPKCS1v15(m.FillBytes(make([]byte, k)))
+	require.NoError(t, err)
+	assert.Equal(t, msg, recovered)
+	assert.Equal(t, oracle.Queries()-1, stats.Queries)
+	t.Logf("%d bit modulus: %d queries over %d iterations", bits, stats.Queries, stats.Iterations)
+}
+
+func TestBleichenbacher(t *testing.T) {
+	t.Run("256 bit modulus", func(t *testing.T) {
+		testBleichenbacher(t, 256)
+	})
+
+	t.Run("768 bit modulus", func(t *testing.T) {
+		testBleichenbacher(t, 768)
+	})
+}
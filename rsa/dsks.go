@@ -0,0 +1,163 @@
+package rsa
+
+import (
+	"crypto"
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+// smoothBound keeps every factor of p-1 small enough that DiscreteLog's brute
+// force over each one is quick.
+const smoothBound = 1 << 12
+
+// smallPrimes lists the odd primes below bound.
+func smallPrimes(bound int) []uint64 {
+	composite := make([]bool, bound)
+	primes := []uint64{}
+	for i := 3; i < bound; i += 2 {
+		if composite[i] {
+			continue
+		}
+		primes = append(primes, uint64(i))
+		for j := i * i; j < bound; j += 2 * i {
+			composite[j] = true
+		}
+	}
+	return primes
+}
+
+// smoothPrime finds a prime p of exactly bits bits with p-1 = 2 * (distinct
+// odd primes below smoothBound), none of them in exclude. It returns p and
+// the factors of p-1.
+func smoothPrime(bits int, exclude map[uint64]bool) (*big.Int, []uint64, error) {
+	pool := []uint64{}
+	for _, r := range smallPrimes(smoothBound) {
+		if !exclude[r] {
+			pool = append(pool, r)
+		}
+	}
+
+	for {
+		factors := []uint64{2}
+		used := make(map[uint64]bool)
+		product := big.NewInt(2)
+		for product.BitLen() < bits {
+			i, err := rand.Int(rand.Reader, big.NewInt(int64(len(pool))))
+			if err != nil {
+				return nil, nil, err
+			}
+			r := pool[i.Int64()]
+			if used[r] {
+				continue
+			}
+			used[r] = true
+			factors = append(factors, r)
+			product.Mul(product, new(big.Int).SetUint64(r))
+		}
+
+		p := product.Add(product, bigOne)
+		if p.BitLen() == bits && p.ProbablyPrime(20) {
+			return p, factors, nil
+		}
+	}
+}
+
+// smoothHalf finds a smooth prime p where both s and m generate Z_p* and
+// returns it with x = log_s(m) mod p-1. Since m is a generator too, x is
+// coprime to p-1 and can be part of an invertible exponent.
+func smoothHalf(s, m *big.Int, bits int, exclude map[uint64]bool) (*big.Int, []uint64, *big.Int, error) {
+	for {
+		p, factors, err := smoothPrime(bits, exclude)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if !isGenerator(s, p, factors) || !isGenerator(m, p, factors) {
+			continue
+		}
+
+		x, err := DiscreteLog(s, m, p, factors)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		return p, factors, x, nil
+	}
+}
+
+func isGenerator(g, p *big.Int, factors []uint64) bool {
+	pMinusOne := new(big.Int).Sub(p, bigOne)
+	for _, r := range factors {
+		exp := new(big.Int).Div(pMinusOne, new(big.Int).SetUint64(r))
+		if new(big.Int).Exp(g, exp, p).Cmp(bigOne) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// DuplicateSignatureKey builds a new key pair, with a modulus the same size
+// as pub's, under which sig is a valid PKCS#1 v1.5 signature on target. The
+// primes are chosen so p-1 and q-1 are smooth, which makes solving
+// s^e = pad(target) for e easy in each half: Pohlig-Hellman gives e mod p-1
+// and e mod q-1 and the CRT joins them. Nothing in the signature commits to
+// the key, so whoever the verifier trusts for that key gets fooled.
+func DuplicateSignatureKey(pub *PublicKey, hash crypto.Hash, sig, target []byte) (*PrivateKey, error) {
+	_, digest, err := hashMessage(hash, target)
+	if err != nil {
+		return nil, err
+	}
+	k := (pub.N.BitLen() + 7) / 8
+	if len(sig) != k {
+		return nil, errors.New("rsa: signature is the wrong length")
+	}
+	em, err := PKCS1v15Encode(hash, digest, k)
+	if err != nil {
+		return nil, err
+	}
+	s := new(big.Int).SetBytes(sig)
+	m := new(big.Int).SetBytes(em)
+
+	bits := pub.N.BitLen()
+	for {
+		p, pFactors, xp, err := smoothHalf(s, m, bits-bits/2, nil)
+		if err != nil {
+			return nil, err
+		}
+		// p-1 and q-1 only share the 2, so (p-1) and (q-1)/2 are coprime
+		exclude := make(map[uint64]bool)
+		for _, r := range pFactors {
+			exclude[r] = true
+		}
+		q, _, xq, err := smoothHalf(s, m, bits/2, exclude)
+		if err != nil {
+			return nil, err
+		}
+
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != bits || n.Cmp(s) <= 0 {
+			continue
+		}
+
+		// Both logs are odd, so they agree mod 2
+		pMinusOne := new(big.Int).Sub(p, bigOne)
+		halfQ := new(big.Int).Rsh(new(big.Int).Sub(q, bigOne), 1)
+		e, err := CRT(
+			[]*big.Int{xp, new(big.Int).Mod(xq, halfQ)},
+			[]*big.Int{pMinusOne, halfQ},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		lambda := new(big.Int).Mul(pMinusOne, halfQ)
+		d, err := InvMod(e, lambda)
+		if err != nil {
+			return nil, err
+		}
+
+		return &PrivateKey{
+			PublicKey: PublicKey{N: n, E: e},
+			D:         d,
+		}, nil
+	}
+}
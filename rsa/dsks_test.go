@@ -0,0 +1,49 @@
+package rsa
+
+import (
+	"crypto"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSmoothPrime(t *testing.T) {
+	p, factors, err := smoothPrime(256, map[uint64]bool{3: true})
+	require.NoError(t, err)
+	assert.Equal(t, 256, p.BitLen())
+	assert.True(t, p.ProbablyPrime(20))
+
+	product := big.NewInt(1)
+	for _, r := range factors {
+		assert.Less(t, r, uint64(smoothBound))
+		assert.NotEqual(t, uint64(3), r)
+		product.Mul(product, new(big.Int).SetUint64(r))
+	}
+	assert.Equal(t, new(big.Int).Sub(p, bigOne), product)
+}
+
+func TestDuplicateSignatureKey(t *testing.T) {
+	priv, err := GenerateKey(1024, 65537)
+	require.NoError(t, err)
+	msg := []byte("Transfer $10 to Bob")
+	target := []byte("Transfer $1000000 to Eve")
+
+	sig, err := SignPKCS1v15(priv, crypto.SHA256, msg)
+	require.NoError(t, err)
+	require.NoError(t, VerifyPKCS1v15(&priv.PublicKey, crypto.SHA256, msg, sig))
+
+	forged, err := DuplicateSignatureKey(&priv.PublicKey, crypto.SHA256, sig, target)
+	require.NoError(t, err)
+	assert.Equal(t, priv.N.BitLen(), forged.N.BitLen())
+	assert.NoError(t, VerifyPKCS1v15(&forged.PublicKey, crypto.SHA256, target, sig))
+	assert.Error(t, VerifyPKCS1v15(&priv.PublicKey, crypto.SHA256, target, sig))
+
+	// The forged key is a working key pair
+	m := big.NewInt(42)
+	assert.Equal(t, m, Decrypt(forged, Encrypt(&forged.PublicKey, m)))
+	sig2, err := SignPKCS1v15(forged, crypto.SHA256, target)
+	require.NoError(t, err)
+	assert.Equal(t, sig, sig2)
+}
@@ -0,0 +1,28 @@
+package rsa
+
+import (
+	"errors"
+	"math/big"
+)
+
+// HastadBroadcast recovers a message that was encrypted to len(ciphertexts)
+// recipients who all use e = len(ciphertexts). The CRT gives m^e mod N1*N2*...,
+// and since m < every Ni, m^e is smaller than the product and the root is exact.
+func HastadBroadcast(ciphertexts, moduli []*big.Int) (*big.Int, error) {
+	e := len(ciphertexts)
+	if e < 2 {
+		return nil, errors.New("rsa: need at least two ciphertexts")
+	}
+
+	combined, err := CRT(ciphertexts, moduli)
+	if err != nil {
+		return nil, err
+	}
+
+	m := NthRoot(combined, e)
+	if new(big.Int).Exp(m, big.NewInt(int64(e)), nil).Cmp(combined) != 0 {
+		return nil, errors.New("rsa: combined ciphertext is not a perfect power")
+	}
+
+	return m, nil
+}
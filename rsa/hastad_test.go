@@ -0,0 +1,28 @@
+package rsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHastadBroadcast(t *testing.T) {
+	msg := []byte("Broadcasting the same message is a bad idea")
+
+	for _, e := range []int{3, 5} {
+		ciphertexts := make([]*big.Int, e)
+		moduli := make([]*big.Int, e)
+		for i := 0; i < e; i++ {
+			priv, err := GenerateKey(512, int64(e))
+			require.NoError(t, err)
+			ciphertexts[i] = new(big.Int).SetBytes(EncryptBytes(&priv.PublicKey, msg))
+			moduli[i] = priv.N
+		}
+
+		m, err := HastadBroadcast(ciphertexts, moduli)
+		require.NoError(t, err)
+		assert.Equal(t, msg, m.Bytes())
+	}
+}
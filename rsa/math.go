@@ -0,0 +1,108 @@
+package rsa
+
+import (
+	"errors"
+	"math/big"
+)
+
+// CRT solves x = residues[i] (mod moduli[i]) for pairwise coprime moduli and
+// returns the unique solution in [0, prod(moduli)).
+func CRT(residues, moduli []*big.Int) (*big.Int, error) {
+	if len(residues) != len(moduli) {
+		return nil, errors.New("rsa: residues and moduli must be the same length")
+	}
+	if len(moduli) == 0 {
+		return nil, errors.New("rsa: no congruences to solve")
+	}
+
+	product := big.NewInt(1)
+	for _, m := range moduli {
+		product.Mul(product, m)
+	}
+
+	result := new(big.Int)
+	for i, m := range moduli {
+		ms := new(big.Int).Div(product, m)
+		inv, err := InvMod(ms, m)
+		if err != nil {
+			return nil, errors.New("rsa: moduli are not pairwise coprime")
+		}
+		term := new(big.Int).Mul(residues[i], ms)
+		term.Mul(term, inv)
+		result.Add(result, term)
+	}
+
+	return result.Mod(result, product), nil
+}
+
+// NthRoot returns floor(x^(1/n)) using Newton's method on integers so large
+// values don't lose precision the way a float64 root would.
+func NthRoot(x *big.Int, n int) *big.Int {
+	if n < 1 {
+		panic("Cannot take root with n < 1")
+	}
+	if x.Sign() < 0 {
+		panic("Cannot take root of negative number")
+	}
+	if n == 1 || x.Cmp(bigOne) <= 0 {
+		return new(big.Int).Set(x)
+	}
+
+	bigN := big.NewInt(int64(n))
+	nMinusOne := big.NewInt(int64(n - 1))
+
+	// Start from a power of two that is guaranteed to be above the root so the
+	// iteration decreases monotonically until it reaches the floor.
+	r := new(big.Int).Lsh(bigOne, uint((x.BitLen()+n-1)/n))
+	pow, next := new(big.Int), new(big.Int)
+
+	for {
+		pow.Exp(r, nMinusOne, nil)
+		next.Div(x, pow)
+		pow.Mul(r, nMinusOne)
+		next.Add(next, pow)
+		next.Div(next, bigN)
+		if next.Cmp(r) >= 0 {
+			return r
+		}
+		r.Set(next)
+	}
+}
+
+// DiscreteLog finds x with g^x = y (mod p) by Pohlig-Hellman, where factors
+// are the distinct primes of p-1 and each divides it only once. For each r
+// it brute forces the log in the subgroup of order r and then combines them
+// with the CRT, so it is only fast when p-1 is smooth.
+func DiscreteLog(g, y, p *big.Int, factors []uint64) (*big.Int, error) {
+	pMinusOne := new(big.Int).Sub(p, bigOne)
+	residues := make([]*big.Int, len(factors))
+	moduli := make([]*big.Int, len(factors))
+
+	for i, r := range factors {
+		bigR := new(big.Int).SetUint64(r)
+		exp, rem := new(big.Int).DivMod(pMinusOne, bigR, new(big.Int))
+		if rem.Sign() != 0 {
+			return nil, errors.New("rsa: factor does not divide p-1")
+		}
+		gr := new(big.Int).Exp(g, exp, p)
+		yr := new(big.Int).Exp(y, exp, p)
+
+		cur := big.NewInt(1)
+		found := false
+		for k := uint64(0); k < r; k++ {
+			if cur.Cmp(yr) == 0 {
+				residues[i] = new(big.Int).SetUint64(k)
+				found = true
+				break
+			}
+			cur.Mul(cur, gr)
+			cur.Mod(cur, p)
+		}
+		if !found {
+			return nil, errors.New("rsa: no discrete log exists")
+		}
+		moduli[i] = bigR
+	}
+
+	return CRT(residues, moduli)
+}
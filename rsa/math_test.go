@@ -0,0 +1,69 @@
+package rsa
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRT(t *testing.T) {
+	t.Run("Small system", func(t *testing.T) {
+		residues := []*big.Int{big.NewInt(2), big.NewInt(3), big.NewInt(2)}
+		moduli := []*big.Int{big.NewInt(3), big.NewInt(5), big.NewInt(7)}
+		x, err := CRT(residues, moduli)
+		require.NoError(t, err)
+		assert.Equal(t, int64(23), x.Int64())
+	})
+
+	t.Run("Moduli not coprime", func(t *testing.T) {
+		residues := []*big.Int{big.NewInt(1), big.NewInt(1)}
+		moduli := []*big.Int{big.NewInt(4), big.NewInt(6)}
+		_, err := CRT(residues, moduli)
+		assert.Error(t, err)
+	})
+}
+
+func TestNthRoot(t *testing.T) {
+	t.Run("Small values", func(t *testing.T) {
+		assert.Equal(t, int64(0), NthRoot(big.NewInt(0), 3).Int64())
+		assert.Equal(t, int64(1), NthRoot(big.NewInt(1), 3).Int64())
+		assert.Equal(t, int64(3), NthRoot(big.NewInt(27), 3).Int64())
+		assert.Equal(t, int64(3), NthRoot(big.NewInt(63), 3).Int64())
+		assert.Equal(t, int64(4), NthRoot(big.NewInt(64), 3).Int64())
+		assert.Equal(t, int64(10), NthRoot(big.NewInt(120), 2).Int64())
+	})
+
+	t.Run("Floor of large roots", func(t *testing.T) {
+		limit := new(big.Int).Lsh(bigOne, 1024)
+		for n := 2; n <= 7; n++ {
+			x, err := rand.Int(rand.Reader, limit)
+			require.NoError(t, err)
+			r := NthRoot(x, n)
+			e := big.NewInt(int64(n))
+			above := new(big.Int).Add(r, bigOne)
+			assert.True(t, new(big.Int).Exp(r, e, nil).Cmp(x) <= 0)
+			assert.True(t, new(big.Int).Exp(above, e, nil).Cmp(x) > 0)
+		}
+	})
+}
+
+func TestDiscreteLog(t *testing.T) {
+	// 2 * 3 * 5 * 7 * 11 * 13 + 1 = 30031 = 59 * 509 isn't prime, but
+	// 2 * 3 * 5 * 7 * 13 + 1 = 2731 is, and 3 generates it
+	p := big.NewInt(2731)
+	factors := []uint64{2, 3, 5, 7, 13}
+	g := big.NewInt(3)
+
+	for _, x := range []int64{0, 1, 2, 1000, 2729} {
+		y := new(big.Int).Exp(g, big.NewInt(x), p)
+		got, err := DiscreteLog(g, y, p, factors)
+		require.NoError(t, err)
+		assert.Equal(t, x, got.Int64())
+	}
+
+	_, err := DiscreteLog(big.NewInt(4), g, p, factors)
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+package rsa
+
+import (
+	"math/big"
+)
+
+// RSAParityOracle reports whether the plaintext of c is even, which is all
+// the parity attack needs.
+type RSAParityOracle interface {
+	IsEven(c *big.Int) bool
+}
+
+type parityServer struct {
+	priv *PrivateKey
+}
+
+func NewParityOracle(priv *PrivateKey) RSAParityOracle {
+	return &parityServer{priv: priv}
+}
+
+func (s *parityServer) IsEven(c *big.Int) bool {
+	return Decrypt(s.priv, c).Bit(0) == 0
+}
+
+// ParityAttack decrypts c one bit at a time. Multiplying c by 2^e doubles
+// the plaintext, and since N is odd, 2m mod N is even exactly when 2m didn't
+// wrap, halving the interval m can be in. The bounds are kept as exact
+// rationals so the last byte isn't lost to rounding. If progress is not nil
+// it is called with the current upper bound after every bit.
+func ParityAttack(pub *PublicKey, c *big.Int, oracle RSAParityOracle, progress func(upper *big.Int)) *big.Int {
+	double := new(big.Int).Exp(bigTwo, pub.E, pub.N)
+	cur := new(big.Int).Set(c)
+	lo := new(big.Rat)
+	hi := new(big.Rat).SetInt(pub.N)
+	half := big.NewRat(1, 2)
+	mid := new(big.Rat)
+
+	for i := 0; i < pub.N.BitLen(); i++ {
+		cur.Mul(cur, double)
+		cur.Mod(cur, pub.N)
+
+		mid.Add(lo, hi)
+		mid.Mul(mid, half)
+		if oracle.IsEven(cur) {
+			hi.Set(mid)
+		} else {
+			lo.Set(mid)
+		}
+
+		if progress != nil {
+			progress(new(big.Int).Quo(hi.Num(), hi.Denom()))
+		}
+	}
+
+	// The plaintext is the only integer left in [lo, hi)
+	m := new(big.Int).Add(lo.Num(), lo.Denom())
+	m.Sub(m, bigOne)
+	return m.Quo(m, lo.Denom())
+}
@@ -0,0 +1,46 @@
+package rsa
+
+import (
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParityAttack(t *testing.T) {
+	priv, err := GenerateKey(1024, 65537)
+	require.NoError(t, err)
+	oracle := NewParityOracle(priv)
+
+	t.Run("Oracle reports parity", func(t *testing.T) {
+		assert.True(t, oracle.IsEven(Encrypt(&priv.PublicKey, big.NewInt(10))))
+		assert.False(t, oracle.IsEven(Encrypt(&priv.PublicKey, big.NewInt(11))))
+	})
+
+	t.Run("Decrypt challenge 46 message", func(t *testing.T) {
+		msg, err := base64.StdEncoding.DecodeString("VGhhdCdzIHdoeSBJIGZvdW5kIHlvdSBkb24ndCBwbGF5IGFyb3VuZCB3aXRoIHRoZSBGdW5reSBDb2xkIE1lZGluYQ==")
+		require.NoError(t, err)
+		c := Encrypt(&priv.PublicKey, new(big.Int).SetBytes(msg))
+
+		steps := 0
+		var last *big.Int
+		m := ParityAttack(&priv.PublicKey, c, oracle, func(upper *big.Int) {
+			steps++
+			last = upper
+		})
+
+		assert.Equal(t, string(msg), string(m.Bytes()))
+		assert.Equal(t, priv.N.BitLen(), steps)
+		assert.Equal(t, msg, last.Bytes())
+	})
+
+	t.Run("Recover small and odd plaintexts exactly", func(t *testing.T) {
+		for _, v := range []int64{0, 1, 2, 255, 65537} {
+			c := Encrypt(&priv.PublicKey, big.NewInt(v))
+			m := ParityAttack(&priv.PublicKey, c, oracle, nil)
+			assert.Equal(t, v, m.Int64())
+		}
+	})
+}
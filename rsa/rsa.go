@@ -0,0 +1,121 @@
+package rsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+)
+
+var (
+	bigOne = big.NewInt(1)
+	bigTwo = big.NewInt(2)
+)
+
+var ErrNoInverse = errors.New("rsa: no modular inverse exists")
+
+type PublicKey struct {
+	N *big.Int
+	E *big.Int
+}
+
+type PrivateKey struct {
+	PublicKey
+	D *big.Int
+}
+
+// InvMod finds x such that a*x = 1 (mod m) using the extended Euclidean
+// algorithm. Returns ErrNoInverse if a and m are not coprime.
+func InvMod(a, m *big.Int) (*big.Int, error) {
+	if m.Sign() <= 0 {
+		return nil, ErrNoInverse
+	}
+
+	// Track r_i = s_i*a + t_i*m, we only need the s coefficients
+	oldR, r := new(big.Int).Mod(a, m), new(big.Int).Set(m)
+	oldS, s := big.NewInt(1), big.NewInt(0)
+	q, tmp := new(big.Int), new(big.Int)
+
+	for r.Sign() != 0 {
+		q.Div(oldR, r)
+
+		tmp.Mul(q, r)
+		oldR, r = r, tmp.Sub(oldR, tmp)
+		tmp = new(big.Int)
+
+		tmp.Mul(q, s)
+		oldS, s = s, tmp.Sub(oldS, tmp)
+		tmp = new(big.Int)
+	}
+
+	if oldR.Cmp(bigOne) != 0 {
+		return nil, ErrNoInverse
+	}
+
+	return oldS.Mod(oldS, m), nil
+}
+
+// GenerateKey creates a key pair with a modulus of the given bit length and
+// public exponent e. Primes are regenerated until e is invertible mod the
+// totient.
+func GenerateKey(bits int, e int64) (*PrivateKey, error) {
+	if bits < 16 {
+		return nil, errors.New("rsa: key size too small")
+	}
+	if e < 3 || e%2 == 0 {
+		return nil, errors.New("rsa: public exponent must be odd and at least 3")
+	}
+
+	E := big.NewInt(e)
+	for {
+		p, err := rand.Prime(rand.Reader, bits-bits/2)
+		if err != nil {
+			return nil, err
+		}
+		q, err := rand.Prime(rand.Reader, bits/2)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != bits {
+			continue
+		}
+
+		et := new(big.Int).Mul(
+			new(big.Int).Sub(p, bigOne),
+			new(big.Int).Sub(q, bigOne),
+		)
+		d, err := InvMod(E, et)
+		if err != nil {
+			continue
+		}
+
+		return &PrivateKey{
+			PublicKey: PublicKey{N: n, E: E},
+			D:         d,
+		}, nil
+	}
+}
+
+func Encrypt(pub *PublicKey, m *big.Int) *big.Int {
+	return new(big.Int).Exp(m, pub.E, pub.N)
+}
+
+func Decrypt(priv *PrivateKey, c *big.Int) *big.Int {
+	return new(big.Int).Exp(c, priv.D, priv.N)
+}
+
+// EncryptBytes treats msg as a big-endian integer. Leading zero bytes are not
+// preserved, this is textbook RSA with no padding.
+func EncryptBytes(pub *PublicKey, msg []byte) []byte {
+	m := new(big.Int).SetBytes(msg)
+	return Encrypt(pub, m).Bytes()
+}
+
+func DecryptBytes(priv *PrivateKey, cText []byte) []byte {
+	c := new(big.Int).SetBytes(cText)
+	return Decrypt(priv, c).Bytes()
+}
@@ -0,0 +1,62 @@
+package rsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvMod(t *testing.T) {
+	t.Run("Challenge example", func(t *testing.T) {
+		inv, err := InvMod(big.NewInt(17), big.NewInt(3120))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2753), inv.Int64())
+	})
+
+	t.Run("Matches math/big", func(t *testing.T) {
+		m := big.NewInt(1000000007)
+		for a := int64(1); a < 200; a++ {
+			inv, err := InvMod(big.NewInt(a), m)
+			require.NoError(t, err)
+			assert.Equal(t, new(big.Int).ModInverse(big.NewInt(a), m), inv)
+		}
+	})
+
+	t.Run("No inverse when not coprime", func(t *testing.T) {
+		_, err := InvMod(big.NewInt(6), big.NewInt(9))
+		assert.ErrorIs(t, err, ErrNoInverse)
+	})
+}
+
+func TestRSA(t *testing.T) {
+	t.Run("Generated key has requested size", func(t *testing.T) {
+		priv, err := GenerateKey(512, 3)
+		require.NoError(t, err)
+		assert.Equal(t, 512, priv.N.BitLen())
+		assert.Equal(t, int64(3), priv.E.Int64())
+	})
+
+	t.Run("Encrypt and decrypt number", func(t *testing.T) {
+		priv, err := GenerateKey(512, 65537)
+		require.NoError(t, err)
+		m := big.NewInt(42)
+		c := Encrypt(&priv.PublicKey, m)
+		assert.Equal(t, m, Decrypt(priv, c))
+	})
+
+	t.Run("Encrypt and decrypt bytes", func(t *testing.T) {
+		priv, err := GenerateKey(1024, 3)
+		require.NoError(t, err)
+		msg := []byte("Attack at dawn")
+		cText := EncryptBytes(&priv.PublicKey, msg)
+		assert.NotEqual(t, msg, cText)
+		assert.Equal(t, msg, DecryptBytes(priv, cText))
+	})
+
+	t.Run("Rejects even exponent", func(t *testing.T) {
+		_, err := GenerateKey(512, 4)
+		assert.Error(t, err)
+	})
+}
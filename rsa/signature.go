@@ -0,0 +1,175 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	"errors"
+	"math/big"
+)
+
+var ErrVerification = errors.New("rsa: verification error")
+
+// ASN.1 DER encoded DigestInfo headers, the hash bytes follow directly.
+var digestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA1:   {0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e, 0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14},
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+}
+
+func hashMessage(hash crypto.Hash, msg []byte) ([]byte, []byte, error) {
+	prefix, ok := digestInfoPrefixes[hash]
+	if !ok || !hash.Available() {
+		return nil, nil, errors.New("rsa: unsupported hash function")
+	}
+	h := hash.New()
+	h.Write(msg)
+	return prefix, h.Sum(nil), nil
+}
+
+// PKCS1v15Encode builds the k byte block 00 01 FF .. FF 00 DigestInfo digest.
+func PKCS1v15Encode(hash crypto.Hash, digest []byte, k int) ([]byte, error) {
+	prefix, ok := digestInfoPrefixes[hash]
+	if !ok {
+		return nil, errors.New("rsa: unsupported hash function")
+	}
+	if len(digest) != hash.Size() {
+		return nil, errors.New("rsa: digest is the wrong length")
+	}
+
+	tLen := len(prefix) + len(digest)
+	if k < tLen+11 {
+		return nil, errors.New("rsa: key too short for digest")
+	}
+
+	em := make([]byte, k)
+	em[1] = 0x01
+	for i := 2; i < k-tLen-1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[k-tLen:], prefix)
+	copy(em[k-len(digest):], digest)
+
+	return em, nil
+}
+
+func SignPKCS1v15(priv *PrivateKey, hash crypto.Hash, msg []byte) ([]byte, error) {
+	_, digest, err := hashMessage(hash, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	k := (priv.N.BitLen() + 7) / 8
+	em, err := PKCS1v15Encode(hash, digest, k)
+	if err != nil {
+		return nil, err
+	}
+
+	s := Decrypt(priv, new(big.Int).SetBytes(em))
+	return s.FillBytes(make([]byte, k)), nil
+}
+
+// openSignature raises the signature to e and returns the k byte block.
+func openSignature(pub *PublicKey, sig []byte) ([]byte, error) {
+	k := (pub.N.BitLen() + 7) / 8
+	if len(sig) != k {
+		return nil, ErrVerification
+	}
+	s := new(big.Int).SetBytes(sig)
+	if s.Cmp(pub.N) >= 0 {
+		return nil, ErrVerification
+	}
+
+	return Encrypt(pub, s).FillBytes(make([]byte, k)), nil
+}
+
+// VerifyPKCS1v15 rebuilds the whole expected block and compares it, so there
+// is nowhere to hide extra bytes.
+func VerifyPKCS1v15(pub *PublicKey, hash crypto.Hash, msg, sig []byte) error {
+	_, digest, err := hashMessage(hash, msg)
+	if err != nil {
+		return err
+	}
+	em, err := openSignature(pub, sig)
+	if err != nil {
+		return err
+	}
+	expected, err := PKCS1v15Encode(hash, digest, len(em))
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(em, expected) {
+		return ErrVerification
+	}
+
+	return nil
+}
+
+// VerifyPKCS1v15Sloppy parses the block left to right the way a naive
+// implementation would: it skips the FF padding, finds the DigestInfo and
+// compares the hash, but never checks that the hash ends the block.
+func VerifyPKCS1v15Sloppy(pub *PublicKey, hash crypto.Hash, msg, sig []byte) error {
+	prefix, digest, err := hashMessage(hash, msg)
+	if err != nil {
+		return err
+	}
+	em, err := openSignature(pub, sig)
+	if err != nil {
+		return err
+	}
+
+	if em[0] != 0x00 || em[1] != 0x01 {
+		return ErrVerification
+	}
+	i := 2
+	for i < len(em) && em[i] == 0xff {
+		i++
+	}
+	if i == 2 || i >= len(em) || em[i] != 0x00 {
+		return ErrVerification
+	}
+	rest := em[i+1:]
+
+	if !bytes.HasPrefix(rest, prefix) {
+		return ErrVerification
+	}
+	rest = rest[len(prefix):]
+	if len(rest) < len(digest) || !bytes.Equal(rest[:len(digest)], digest) {
+		return ErrVerification
+	}
+
+	return nil
+}
+
+// ForgePKCS1v15Signature produces a signature for msg that a sloppy verifier
+// accepts without knowing the private key. It builds 00 01 FF 00 DigestInfo
+// digest followed by FF garbage and takes the floor e-th root. As long as
+// the garbage is long enough the error from rounding stays inside it.
+func ForgePKCS1v15Signature(pub *PublicKey, hash crypto.Hash, msg []byte) ([]byte, error) {
+	prefix, digest, err := hashMessage(hash, msg)
+	if err != nil {
+		return nil, err
+	}
+	if !pub.E.IsInt64() {
+		return nil, errors.New("rsa: public exponent too large to forge")
+	}
+
+	k := (pub.N.BitLen() + 7) / 8
+	head := []byte{0x00, 0x01, 0xff, 0x00}
+	head = append(head, prefix...)
+	head = append(head, digest...)
+	if len(head) > k {
+		return nil, errors.New("rsa: key too short for digest")
+	}
+
+	block := bytes.Repeat([]byte{0xff}, k)
+	copy(block, head)
+
+	s := NthRoot(new(big.Int).SetBytes(block), int(pub.E.Int64()))
+	forged := Encrypt(pub, s).FillBytes(make([]byte, k))
+	if !bytes.HasPrefix(forged, head) {
+		return nil, errors.New("rsa: not enough room to hide the rounding error")
+	}
+
+	return s.FillBytes(make([]byte, k)), nil
+}
@@ -0,0 +1,47 @@
+package rsa
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPKCS1v15Signature(t *testing.T) {
+	priv, err := GenerateKey(1024, 3)
+	require.NoError(t, err)
+	pub := &priv.PublicKey
+	msg := []byte("hi mom")
+
+	t.Run("Legitimate signature passes both verifiers", func(t *testing.T) {
+		for _, hash := range []crypto.Hash{crypto.SHA1, crypto.SHA256} {
+			sig, err := SignPKCS1v15(priv, hash, msg)
+			require.NoError(t, err)
+			assert.NoError(t, VerifyPKCS1v15(pub, hash, msg, sig))
+			assert.NoError(t, VerifyPKCS1v15Sloppy(pub, hash, msg, sig))
+			assert.Error(t, VerifyPKCS1v15(pub, hash, []byte("hi dad"), sig))
+			assert.Error(t, VerifyPKCS1v15Sloppy(pub, hash, []byte("hi dad"), sig))
+		}
+	})
+
+	t.Run("Forged SHA-1 signature fools only the sloppy verifier", func(t *testing.T) {
+		sig, err := ForgePKCS1v15Signature(pub, crypto.SHA1, msg)
+		require.NoError(t, err)
+		assert.NoError(t, VerifyPKCS1v15Sloppy(pub, crypto.SHA1, msg, sig))
+		assert.ErrorIs(t, VerifyPKCS1v15(pub, crypto.SHA1, msg, sig), ErrVerification)
+	})
+
+	t.Run("Forged SHA-256 signature needs a bigger key", func(t *testing.T) {
+		_, err := ForgePKCS1v15Signature(pub, crypto.SHA256, msg)
+		assert.Error(t, err)
+
+		bigPriv, err := GenerateKey(2048, 3)
+		require.NoError(t, err)
+		bigPub := &bigPriv.PublicKey
+		sig, err := ForgePKCS1v15Signature(bigPub, crypto.SHA256, msg)
+		require.NoError(t, err)
+		assert.NoError(t, VerifyPKCS1v15Sloppy(bigPub, crypto.SHA256, msg, sig))
+		assert.ErrorIs(t, VerifyPKCS1v15(bigPub, crypto.SHA256, msg, sig), ErrVerification)
+	})
+}
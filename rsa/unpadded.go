@@ -0,0 +1,81 @@
+package rsa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"sync"
+)
+
+var (
+	ErrAlreadyDecrypted = errors.New("rsa: ciphertext has already been decrypted")
+	ErrCiphertextRange  = errors.New("rsa: ciphertext out of range")
+)
+
+// DecryptionServer decrypts any ciphertext it is given, but only once. It
+// remembers the hash of everything it has decrypted and rejects repeats.
+// Ciphertexts must be in [0, N), otherwise c + N would be a new repeat.
+type DecryptionServer struct {
+	priv *PrivateKey
+	mu   sync.Mutex
+	seen map[[sha256.Size]byte]struct{}
+}
+
+func NewDecryptionServer(priv *PrivateKey) *DecryptionServer {
+	return &DecryptionServer{
+		priv: priv,
+		seen: make(map[[sha256.Size]byte]struct{}),
+	}
+}
+
+func (s *DecryptionServer) PublicKey() *PublicKey {
+	return &s.priv.PublicKey
+}
+
+func (s *DecryptionServer) Decrypt(c *big.Int) (*big.Int, error) {
+	if c.Sign() < 0 || c.Cmp(s.priv.N) >= 0 {
+		return nil, ErrCiphertextRange
+	}
+	hash := sha256.Sum256(c.Bytes())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.seen[hash]; exists {
+		return nil, ErrAlreadyDecrypted
+	}
+	s.seen[hash] = struct{}{}
+
+	return Decrypt(s.priv, c), nil
+}
+
+// BlindingAttack recovers the plaintext of c from an oracle that will not
+// decrypt c itself. Submitting S^e * c gives back S * m, and dividing out S
+// mod N leaves m.
+func BlindingAttack(pub *PublicKey, c *big.Int, decrypt func(*big.Int) (*big.Int, error)) (*big.Int, error) {
+	limit := new(big.Int).Sub(pub.N, bigTwo)
+	var s, sInv *big.Int
+	for {
+		r, err := rand.Int(rand.Reader, limit)
+		if err != nil {
+			return nil, err
+		}
+		s = r.Add(r, bigTwo)
+		sInv, err = InvMod(s, pub.N)
+		if err == nil {
+			break
+		}
+	}
+
+	blinded := new(big.Int).Exp(s, pub.E, pub.N)
+	blinded.Mul(blinded, c)
+	blinded.Mod(blinded, pub.N)
+
+	p, err := decrypt(blinded)
+	if err != nil {
+		return nil, err
+	}
+
+	m := new(big.Int).Mul(p, sInv)
+	return m.Mod(m, pub.N), nil
+}
@@ -0,0 +1,38 @@
+package rsa
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnpaddedMessageRecovery(t *testing.T) {
+	priv, err := GenerateKey(1024, 65537)
+	require.NoError(t, err)
+	server := NewDecryptionServer(priv)
+	msg := []byte(`{time: 1356304276, social: '555-55-5555'}`)
+	c := Encrypt(server.PublicKey(), new(big.Int).SetBytes(msg))
+
+	t.Run("Server refuses to decrypt twice", func(t *testing.T) {
+		m, err := server.Decrypt(c)
+		require.NoError(t, err)
+		assert.Equal(t, msg, m.Bytes())
+
+		_, err = server.Decrypt(c)
+		assert.ErrorIs(t, err, ErrAlreadyDecrypted)
+
+		// c + N is the same ciphertext mod N
+		_, err = server.Decrypt(new(big.Int).Add(c, priv.N))
+		assert.ErrorIs(t, err, ErrCiphertextRange)
+		_, err = server.Decrypt(new(big.Int).Neg(c))
+		assert.ErrorIs(t, err, ErrCiphertextRange)
+	})
+
+	t.Run("Blinding recovers the message", func(t *testing.T) {
+		m, err := BlindingAttack(server.PublicKey(), c, server.Decrypt)
+		require.NoError(t, err)
+		assert.Equal(t, msg, m.Bytes())
+	})
+}
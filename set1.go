@@ -155,6 +155,16 @@ func EncryptECB(pText []byte, key []byte) []byte {
 }
 
 func DecryptECB(cyphertext []byte, key []byte) ([]byte, error) {
+	plaintext, err := DecryptECBNoPad(cyphertext, key)
+	if err != nil {
+		return nil, err
+	}
+	return StripPKCSPad(plaintext), nil
+}
+
+// DecryptECBNoPad decrypts whole blocks and leaves any padding in place, for
+// when the blocks aren't the end of a padded message.
+func DecryptECBNoPad(cyphertext []byte, key []byte) ([]byte, error) {
 	cipher, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -165,7 +175,7 @@ func DecryptECB(cyphertext []byte, key []byte) ([]byte, error) {
 	for p := 0; p < len(plaintext); p += cipher.BlockSize() {
 		cipher.Decrypt(plaintext[p:], cyphertext[p:])
 	}
-	return StripPKCSPad(plaintext), nil
+	return plaintext, nil
 }
 
 func DetectAESECB(lines [][]byte, blocksize int) [][]byte {
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"crypto/aes"
+	"encoding/binary"
+)
+
+// CTR encrypts or decrypts text with AES in the challenge 18 CTR format: the
+// keystream blocks are AES(key, nonce || counter) with both halves as 64 bit
+// little endian integers.
+func CTR(text, key []byte, nonce uint64) []byte {
+	cipher, err := aes.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+
+	blockSize := cipher.BlockSize()
+	counterBlock := make([]byte, blockSize)
+	binary.LittleEndian.PutUint64(counterBlock, nonce)
+	keystream := make([]byte, blockSize)
+	out := make([]byte, len(text))
+
+	for i := 0; i < len(text); i += blockSize {
+		binary.LittleEndian.PutUint64(counterBlock[8:], uint64(i/blockSize))
+		cipher.Encrypt(keystream, counterBlock)
+		for j := i; j < len(text) && j < i+blockSize; j++ {
+			out[j] = text[j] ^ keystream[j-i]
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCTR(t *testing.T) {
+	t.Run("Decrypt challenge 18 string", func(t *testing.T) {
+		cText, err := base64.StdEncoding.DecodeString("L77na/nrFsKvynd6HzOoG7GHTLXsTVu9qvY/2syLXzhPweyyMTJULu/6/kXX0KSvoOLSFQ==")
+		require.NoError(t, err)
+		pText := CTR(cText, []byte("YELLOW SUBMARINE"), 0)
+		assert.Equal(t, "Yo, VIP Let's kick it Ice, Ice, baby Ice, Ice, baby ", string(pText))
+	})
+
+	t.Run("Encrypt and decrypt CTR", func(t *testing.T) {
+		key := RandomBytes(16)
+		pText := RandomBytes(100)
+		cText := CTR(pText, key, 42)
+		assert.NotEqual(t, pText, cText)
+		assert.Equal(t, pText, CTR(cText, key, 42))
+	})
+}
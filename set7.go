@@ -0,0 +1,712 @@
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+func CBCMAC(key, iv, msg []byte) []byte {
+	// EncryptCBC pads and xors in place, so give it a copy
+	toMAC := make([]byte, len(msg), len(msg)+len(key))
+	copy(toMAC, msg)
+	cText := EncryptCBC(toMAC, key, iv)
+	return cText[len(cText)-len(key):]
+}
+
+func VerifyCBCMAC(key, iv, msg, mac []byte) bool {
+	return subtle.ConstantTimeCompare(CBCMAC(key, iv, msg), mac) == 1
+}
+
+type Transaction struct {
+	From   int
+	To     int
+	Amount int
+}
+
+// BankServer accepts signed transfer requests over HTTP. With FixedIV unset
+// the request is message || IV || MAC and the message is
+// from=ID&to=ID&amount=N. With FixedIV set the IV is all zeroes, the request
+// is message || MAC and the message is from=ID&tx_list=to:amount(;to:amount)*.
+type BankServer struct {
+	key     []byte
+	FixedIV bool
+
+	mu           sync.Mutex
+	transactions []Transaction
+}
+
+func NewBankServer(key []byte, fixedIV bool) *BankServer {
+	return &BankServer{key: key, FixedIV: fixedIV}
+}
+
+func (s *BankServer) Transactions() []Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Transaction{}, s.transactions...)
+}
+
+func (s *BankServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	blockSize := len(s.key)
+	iv := make([]byte, blockSize)
+	tail := blockSize
+	if !s.FixedIV {
+		tail += blockSize
+	}
+	if len(body) < tail {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	msg := body[:len(body)-tail]
+	mac := body[len(body)-blockSize:]
+	if !s.FixedIV {
+		iv = body[len(body)-tail : len(body)-blockSize]
+	}
+
+	if !VerifyCBCMAC(s.key, iv, msg, mac) {
+		http.Error(w, "invalid MAC", http.StatusForbidden)
+		return
+	}
+
+	var txs []Transaction
+	if s.FixedIV {
+		txs, err = parseTxList(msg)
+	} else {
+		txs, err = parseTransfer(msg)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.transactions = append(s.transactions, txs...)
+	s.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func parseFields(msg []byte) map[string]string {
+	fields := make(map[string]string)
+	for _, f := range strings.Split(string(msg), "&") {
+		k, v, ok := strings.Cut(f, "=")
+		if ok {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+func parseTransfer(msg []byte) ([]Transaction, error) {
+	fields := parseFields(msg)
+	from, err1 := strconv.Atoi(fields["from"])
+	to, err2 := strconv.Atoi(fields["to"])
+	amount, err3 := strconv.Atoi(fields["amount"])
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return nil, errors.New("malformed transfer")
+	}
+
+	return []Transaction{{From: from, To: to, Amount: amount}}, nil
+}
+
+// parseTxList skips any entry it can't make sense of rather than rejecting
+// the whole request.
+func parseTxList(msg []byte) ([]Transaction, error) {
+	fields := parseFields(msg)
+	from, err := strconv.Atoi(fields["from"])
+	if err != nil {
+		return nil, errors.New("malformed transfer")
+	}
+
+	txs := []Transaction{}
+	for _, entry := range strings.Split(fields["tx_list"], ";") {
+		to, amount, ok := strings.Cut(entry, ":")
+		if !ok {
+			continue
+		}
+		toID, err1 := strconv.Atoi(to)
+		amountN, err2 := strconv.Atoi(amount)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		txs = append(txs, Transaction{From: from, To: toID, Amount: amountN})
+	}
+
+	return txs, nil
+}
+
+// BankClient is the web front end that shares the MAC key with the server.
+// It will only sign transfers out of the logged in account.
+type BankClient struct {
+	key []byte
+	ID  int
+}
+
+func NewBankClient(key []byte, id int) *BankClient {
+	return &BankClient{key: key, ID: id}
+}
+
+func (c *BankClient) SignTransfer(to, amount int) []byte {
+	msg := []byte(fmt.Sprintf("from=%d&to=%d&amount=%d", c.ID, to, amount))
+	iv := RandomBytes(len(c.key))
+	req := append(msg, iv...)
+	return append(req, CBCMAC(c.key, iv, msg)...)
+}
+
+func (c *BankClient) SignTxList(txs []Transaction) []byte {
+	entries := make([]string, len(txs))
+	for i, tx := range txs {
+		entries[i] = fmt.Sprintf("%d:%d", tx.To, tx.Amount)
+	}
+	msg := []byte(fmt.Sprintf("from=%d&tx_list=%s", c.ID, strings.Join(entries, ";")))
+	return append(msg, CBCMAC(c.key, make([]byte, len(c.key)), msg)...)
+}
+
+func PostTransfer(url string, req []byte) error {
+	resp, err := http.Post(url, "application/octet-stream", bytes.NewReader(req))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("transfer rejected: %s", strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// ForgeTransferWithIV rewrites the from field of a request the attacker
+// signed for their own account. The from field is in the first block, so
+// flipping the same bits in the IV leaves the MAC unchanged.
+func ForgeTransferWithIV(req []byte, victim int, blockSize int) ([]byte, error) {
+	msgLen := len(req) - 2*blockSize
+	if msgLen < blockSize {
+		return nil, errors.New("request too short")
+	}
+	msg := req[:msgLen]
+	fromField, _, _ := bytes.Cut(msg, []byte("&"))
+	newFrom := []byte(fmt.Sprintf("from=%d", victim))
+	if len(newFrom) != len(fromField) || len(fromField) > blockSize {
+		return nil, errors.New("victim ID must be the same length as the attacker's")
+	}
+
+	forged := append([]byte{}, req...)
+	copy(forged, newFrom)
+	iv := forged[msgLen : msgLen+blockSize]
+	for i := range newFrom {
+		iv[i] ^= msg[i] ^ newFrom[i]
+	}
+
+	return forged, nil
+}
+
+// ForgeTransferByExtension glues an attacker signed tx list onto a captured
+// victim request. With a fixed zero IV the victim's MAC is the chaining value
+// after its padded message, so xoring it into the first block of the
+// attacker's message puts the cipher in the same state as signing the
+// attacker's message alone, and the attacker's MAC is valid for the whole thing.
+// The xored block becomes garbage, which fails if it contains a field separator.
+func ForgeTransferByExtension(victimReq, attackerReq []byte, blockSize int) ([]byte, error) {
+	if len(victimReq) < blockSize || len(attackerReq) < 2*blockSize {
+		return nil, errors.New("request too short")
+	}
+	victimMsg := victimReq[:len(victimReq)-blockSize]
+	victimMAC := victimReq[len(victimReq)-blockSize:]
+
+	padded := PKCSPad(append([]byte{}, victimMsg...), blockSize)
+	extension := append([]byte{}, attackerReq...)
+	FixedXor(extension[:blockSize], victimMAC)
+	if bytes.IndexByte(extension[:blockSize], '&') >= 0 {
+		return nil, errors.New("glue block contains a field separator")
+	}
+
+	return append(padded, extension...), nil
+}
+
+// ForgeCBCMACPreimage returns a message that starts with prefix and has the
+// given CBC-MAC under key and iv. The prefix is padded with spaces to a block
+// boundary, then one glue block is chosen so that, once CBC-MAC appends its
+// own full block of padding, the chain ends on targetMAC.
+func ForgeCBCMACPreimage(key, iv, prefix, targetMAC []byte) ([]byte, error) {
+	blockSize := len(key)
+	if len(targetMAC) != blockSize {
+		return nil, errors.New("MAC must be one block")
+	}
+
+	msg := append([]byte{}, prefix...)
+	if extra := len(msg) % blockSize; extra != 0 {
+		msg = append(msg, bytes.Repeat([]byte{' '}, blockSize-extra)...)
+	}
+
+	// Chaining value after the prefix. CBCMAC would pad, so encrypt a copy
+	// with the padding block dropped.
+	state := iv
+	if len(msg) > 0 {
+		cText := EncryptCBC(append([]byte{}, msg...), key, iv)
+		state = cText[len(msg)-blockSize : len(msg)]
+	}
+
+	// Work backwards from the MAC: E(s2 ^ pad) = MAC and E(state ^ glue) = s2
+	s2, err := DecryptECBNoPad(targetMAC, key)
+	if err != nil {
+		return nil, err
+	}
+	FixedXor(s2, bytes.Repeat([]byte{byte(blockSize)}, blockSize))
+	glue, err := DecryptECBNoPad(s2, key)
+	if err != nil {
+		return nil, err
+	}
+	FixedXor(glue, state)
+
+	return append(msg, glue...), nil
+}
+
+type CompressionCipher int
+
+const (
+	CompressionCTR CompressionCipher = iota
+	CompressionCBC
+)
+
+const SessionID = "TmV2ZXIgcmV2ZWFsIHRoZSBXdS1UYW5nIFNlY3JldCE="
+
+func FormatSessionRequest(sessionID string, body []byte) []byte {
+	return []byte(fmt.Sprintf("POST / HTTP/1.1\n"+
+		"Host: hapless.com\n"+
+		"Cookie: sessionid=%s\n"+
+		"Content-Length: %d\n"+
+		"%s", sessionID, len(body), body))
+}
+
+// NewCompressionOracle returns a function that builds a request around the
+// attacker's body, compresses it, encrypts it under a fresh key and reports
+// only the length of the result.
+func NewCompressionOracle(sessionID string, mode CompressionCipher) func([]byte) int {
+	buf := bytes.Buffer{}
+	w, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		panic(err)
+	}
+	mu := sync.Mutex{}
+
+	return func(body []byte) int {
+		// Setting up a flate.Writer is expensive, so reuse one
+		mu.Lock()
+		defer mu.Unlock()
+		buf.Reset()
+		w.Reset(&buf)
+		w.Write(FormatSessionRequest(sessionID, body))
+		w.Close()
+
+		key := RandomBytes(16)
+		if mode == CompressionCBC {
+			return len(EncryptCBC(buf.Bytes(), key, RandomBytes(16)))
+		}
+		return len(CTR(buf.Bytes(), key, binary.LittleEndian.Uint64(RandomBytes(8))))
+	}
+}
+
+const base64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/="
+
+// Bytes that never appear in the request, used to shift the compressed length
+// across a block boundary
+const compressionFiller = "!@#$%^&*()[]{}<>`;,?_"
+
+// CrackCompressionOracle recovers the session ID one character at a time.
+// The right guess extends the "sessionid=" match that DEFLATE already finds,
+// so known+c+junk compresses a little better than known+junk+c. Both contain
+// the same bytes, which cancels out any bias from the Huffman table. When the
+// oracle pads to a block size the difference only shows at a block boundary,
+// so the comparison is repeated with filler of every length and summed.
+func CrackCompressionOracle(oracle func([]byte) int) (string, error) {
+	known := "sessionid="
+	candidates := base64Alphabet + "\n"
+
+	for {
+		next, err := guessNextCompressed(oracle, known, candidates)
+		if err != nil {
+			return "", err
+		}
+		if next == '\n' {
+			return strings.TrimPrefix(known, "sessionid="), nil
+		}
+		known += string(next)
+	}
+}
+
+func guessNextCompressed(oracle func([]byte) int, known, candidates string) (byte, error) {
+	const junk = "~|"
+	scores := make([]int, len(candidates))
+
+	for padLen := 0; padLen <= len(compressionFiller); padLen++ {
+		filler := compressionFiller[:padLen]
+		for i := 0; i < len(candidates); i++ {
+			c := string(candidates[i])
+			matched := oracle([]byte(filler + known + c + junk))
+			unmatched := oracle([]byte(filler + known + junk + c))
+			scores[i] += unmatched - matched
+		}
+
+		// Stop early once a single guess is ahead and nothing else has scored
+		winners, best := 0, 0
+		for i, s := range scores {
+			if s > 0 {
+				winners++
+				best = i
+			}
+		}
+		if winners == 1 {
+			return candidates[best], nil
+		}
+	}
+
+	return 0, errors.New("could not separate guesses by compressed length")
+}
+
+// md4Condition is one of Wang et al.'s sufficient conditions on a bit of the
+// chaining value produced at step (a1 is step 0, d1 step 1, ...). Bits count
+// from 1 as in the paper. The kinds are '0', '1', '=' (equal to the same bit
+// at step ref) and '!' (not equal). Step -1 is b0 from the IV.
+type md4Condition struct {
+	step int
+	bit  int
+	kind byte
+	ref  int
+}
+
+func bitZero(step, bit int) md4Condition    { return md4Condition{step, bit, '0', 0} }
+func bitOne(step, bit int) md4Condition     { return md4Condition{step, bit, '1', 0} }
+func bitEq(step, bit, ref int) md4Condition { return md4Condition{step, bit, '=', ref} }
+func bitNe(step, bit, ref int) md4Condition { return md4Condition{step, bit, '!', ref} }
+
+var wangConditions = []md4Condition{
+	// a1
+	bitEq(0, 7, -1),
+	// d1
+	bitZero(1, 7), bitEq(1, 8, 0), bitEq(1, 11, 0),
+	// c1
+	bitOne(2, 7), bitOne(2, 8), bitZero(2, 11), bitEq(2, 26, 1),
+	// b1
+	bitOne(3, 7), bitZero(3, 8), bitZero(3, 11), bitZero(3, 26),
+	// a2
+	bitOne(4, 8), bitOne(4, 11), bitZero(4, 26), bitEq(4, 14, 3),
+	// d2
+	bitZero(5, 14), bitEq(5, 19, 4), bitEq(5, 20, 4), bitEq(5, 21, 4), bitEq(5, 22, 4), bitOne(5, 26),
+	// c2
+	bitEq(6, 13, 5), bitZero(6, 14), bitEq(6, 15, 5), bitZero(6, 19), bitZero(6, 20), bitOne(6, 21), bitZero(6, 22),
+	// b2
+	bitOne(7, 13), bitOne(7, 14), bitZero(7, 15), bitEq(7, 17, 6), bitZero(7, 19), bitZero(7, 20), bitZero(7, 21), bitZero(7, 22),
+	// a3
+	bitOne(8, 13), bitOne(8, 14), bitOne(8, 15), bitZero(8, 17), bitZero(8, 19), bitZero(8, 20), bitZero(8, 21),
+	bitEq(8, 23, 7), bitOne(8, 22), bitEq(8, 26, 7),
+	// d3
+	bitOne(9, 13), bitOne(9, 14), bitOne(9, 15), bitZero(9, 17), bitZero(9, 20), bitOne(9, 21), bitOne(9, 22),
+	bitZero(9, 23), bitOne(9, 26), bitEq(9, 30, 8),
+	// c3
+	bitOne(10, 17), bitZero(10, 20), bitZero(10, 21), bitZero(10, 22), bitZero(10, 23), bitZero(10, 26),
+	bitOne(10, 30), bitEq(10, 32, 9),
+	// b3
+	bitZero(11, 20), bitOne(11, 21), bitOne(11, 22), bitEq(11, 23, 10), bitOne(11, 26), bitZero(11, 30), bitZero(11, 32),
+	// a4
+	bitZero(12, 23), bitZero(12, 26), bitEq(12, 27, 11), bitEq(12, 29, 11), bitOne(12, 30), bitZero(12, 32),
+	// d4
+	bitZero(13, 23), bitZero(13, 26), bitOne(13, 27), bitOne(13, 29), bitZero(13, 30), bitOne(13, 32),
+	// c4
+	bitEq(14, 19, 13), bitOne(14, 23), bitOne(14, 26), bitZero(14, 27), bitZero(14, 29), bitZero(14, 30),
+	// b4
+	bitZero(15, 19), bitOne(15, 26), bitOne(15, 27), bitOne(15, 29), bitZero(15, 30),
+	// a5
+	bitEq(16, 19, 14), bitOne(16, 26), bitZero(16, 27), bitOne(16, 29), bitOne(16, 32),
+	// d5
+	bitEq(17, 19, 16), bitEq(17, 26, 15), bitEq(17, 27, 15), bitEq(17, 29, 15), bitEq(17, 32, 15),
+	// c5
+	bitEq(18, 26, 17), bitEq(18, 27, 17), bitEq(18, 29, 17), bitEq(18, 30, 17), bitEq(18, 32, 17),
+	// b5
+	bitEq(19, 29, 18), bitOne(19, 30), bitZero(19, 32),
+	// a6
+	bitOne(20, 29), bitOne(20, 32),
+	// d6
+	bitEq(21, 29, 19),
+	// c6
+	bitEq(22, 29, 21), bitNe(22, 30, 21), bitNe(22, 32, 21),
+	// b9 and a10
+	bitOne(35, 32), bitOne(36, 32),
+}
+
+// conditionsByStep groups wangConditions so each step's can be applied as
+// soon as its value is computed.
+var conditionsByStep = func() [48][]md4Condition {
+	grouped := [48][]md4Condition{}
+	for _, c := range wangConditions {
+		grouped[c.step] = append(grouped[c.step], c)
+	}
+	return grouped
+}()
+
+// md4Chain holds a0, d0, c0, b0 followed by the value produced at each of
+// the 48 steps, so step i lives at index i+4.
+type md4Chain [52]uint32
+
+func (q *md4Chain) at(step int) uint32 {
+	return q[step+4]
+}
+
+// md4StepParams returns the message word, shift and step function for a
+// step of the compression function.
+func md4StepParams(step int) (int, int, func(a, b, c, d, m uint32, s int) uint32) {
+	switch step / 16 {
+	case 0:
+		return md4Round1Order[step], md4Round1Shifts[step%4], MD4Round1Step
+	case 1:
+		return md4Round2Order[step-16], md4Round2Shifts[step%4], MD4Round2Step
+	default:
+		return md4Round3Order[step-32], md4Round3Shifts[step%4], MD4Round3Step
+	}
+}
+
+func (q *md4Chain) compute(step int, x *[16]uint32) uint32 {
+	j := step + 4
+	idx, shift, f := md4StepParams(step)
+	return f(q[j-4], q[j-1], q[j-2], q[j-3], x[idx], shift)
+}
+
+func md4ChainFor(x *[16]uint32) *md4Chain {
+	q := &md4Chain{MD4IV[0], MD4IV[3], MD4IV[2], MD4IV[1]}
+	for step := 0; step < 48; step++ {
+		q[step+4] = q.compute(step, x)
+	}
+	return q
+}
+
+func applyConditions(q *md4Chain, v uint32, step int) uint32 {
+	for _, c := range conditionsByStep[step] {
+		mask := uint32(1) << (c.bit - 1)
+		switch c.kind {
+		case '0':
+			v &^= mask
+		case '1':
+			v |= mask
+		case '=':
+			v = v&^mask | q.at(c.ref)&mask
+		case '!':
+			v = v&^mask | ^q.at(c.ref)&mask
+		}
+	}
+	return v
+}
+
+func conditionsHold(q *md4Chain) bool {
+	for _, c := range wangConditions {
+		mask := uint32(1) << (c.bit - 1)
+		v := q.at(c.step) & mask
+		switch c.kind {
+		case '0':
+			if v != 0 {
+				return false
+			}
+		case '1':
+			if v == 0 {
+				return false
+			}
+		case '=':
+			if v != q.at(c.ref)&mask {
+				return false
+			}
+		case '!':
+			if v == q.at(c.ref)&mask {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// solveRound1Word finds the message word that makes round 1 step produce
+// the value already stored in q.
+func solveRound1Word(q *md4Chain, x *[16]uint32, step int) {
+	j := step + 4
+	x[step] = bits.RotateLeft32(q[j], -md4Round1Shifts[step%4]) - q[j-4] - md4F(q[j-1], q[j-2], q[j-3])
+}
+
+// fixRound2Step forces the conditions on a round 2 step whose message word
+// is also used at round 1 step first. Changing that word changes the round 1
+// value, so the next four words are re-solved to keep every later round 1
+// value the same.
+func fixRound2Step(q *md4Chain, x *[16]uint32, step, first int) {
+	j := step + 4
+	v := applyConditions(q, q.compute(step, x), step)
+	x[first] = bits.RotateLeft32(v, -md4Round2Shifts[step%4]) - q[j-4] - md4G(q[j-1], q[j-2], q[j-3]) - md4Round2K
+	q[first+4] = q.compute(first, x)
+	for i := first + 1; i <= first+4; i++ {
+		solveRound1Word(q, x, i)
+	}
+	q[j] = v
+}
+
+// wangSingleStep makes every round 1 condition hold by fixing each value as
+// it is computed and solving for the message word that produces it.
+func wangSingleStep(x [16]uint32) (*md4Chain, [16]uint32) {
+	q := &md4Chain{MD4IV[0], MD4IV[3], MD4IV[2], MD4IV[1]}
+	for step := 0; step < 16; step++ {
+		q[step+4] = applyConditions(q, q.compute(step, &x), step)
+		solveRound1Word(q, &x, step)
+	}
+	return q, x
+}
+
+// WangModify applies message modification to x: single-step modification for
+// round 1, then multi-step modification to fix a5 and d5 in round 2. Carries
+// from the round 2 fixes occasionally break a round 1 condition, so the
+// result still needs checking.
+func WangModify(x [16]uint32) [16]uint32 {
+	q, x := wangSingleStep(x)
+	fixRound2Step(q, &x, 16, 0)
+	fixRound2Step(q, &x, 17, 4)
+	return x
+}
+
+// WangDifferential returns the partner message for Wang's MD4 differential:
+// m1 + 2^31, m2 + 2^31 - 2^28, m12 - 2^16.
+func WangDifferential(x [16]uint32) [16]uint32 {
+	x[1] += 1 << 31
+	x[2] += 1<<31 - 1<<28
+	x[12] -= 1 << 16
+	return x
+}
+
+// FindMD4Collision tries random messages until one, after modification,
+// collides with its partner. It returns both one block messages and the
+// number of messages tried.
+func FindMD4Collision() ([]byte, []byte, int) {
+	for attempts := 1; ; attempts++ {
+		x := WangModify(MD4BlockWords(RandomBytes(64)))
+		if !conditionsHold(md4ChainFor(&x)) {
+			continue
+		}
+
+		m1 := MD4WordsBlock(x)
+		m2 := MD4WordsBlock(WangDifferential(x))
+		if MD4Compress(MD4IV, m1) == MD4Compress(MD4IV, m2) {
+			return m1, m2, attempts
+		}
+	}
+}
+
+var RC4Cookie, _ = base64.StdEncoding.DecodeString("QkUgU1VSRSBUTyBEUklOSyBZT1VSIE9WQUxUSU5F")
+
+// NewRC4CookieOracle returns a function that encrypts request || cookie with
+// RC4 under a new random key every call.
+func NewRC4CookieOracle(cookie []byte) func([]byte) []byte {
+	return func(request []byte) []byte {
+		pText := append(append([]byte{}, request...), cookie...)
+		return RC4Encrypt(pText, RandomBytes(16))
+	}
+}
+
+// The 16th and 32nd keystream bytes are biased towards 240 and 224
+const (
+	rc4Z16Bias = 240
+	rc4Z32Bias = 224
+)
+
+// rc4Histograms counts ciphertext bytes at positions 15 and 31 over samples
+// oracle calls. Each worker keeps its own counts so there's no contention,
+// and they are added together at the end.
+func rc4Histograms(oracle func([]byte) []byte, request []byte, samples int) [2][256]int {
+	workers := runtime.NumCPU()
+	results := make(chan [2][256]int, workers)
+
+	for w := 0; w < workers; w++ {
+		n := samples / workers
+		if w < samples%workers {
+			n++
+		}
+		go func(n int) {
+			hist := [2][256]int{}
+			for i := 0; i < n; i++ {
+				cText := oracle(request)
+				hist[0][cText[15]]++
+				if len(cText) > 31 {
+					hist[1][cText[31]]++
+				}
+			}
+			results <- hist
+		}(n)
+	}
+
+	merged := [2][256]int{}
+	for w := 0; w < workers; w++ {
+		hist := <-results
+		for z := range hist {
+			for b, count := range hist[z] {
+				merged[z][b] += count
+			}
+		}
+	}
+	return merged
+}
+
+func mostCommonByte(hist *[256]int) byte {
+	best := 0
+	for b, count := range hist {
+		if count > hist[best] {
+			best = b
+		}
+	}
+	return byte(best)
+}
+
+// CrackRC4Cookie recovers a cookie of up to 32 bytes from an oracle that
+// encrypts request || cookie under fresh RC4 keys. Prefixing n bytes puts
+// cookie byte 15-n at keystream byte 16 and byte 31-n at keystream byte 32,
+// so each prefix length recovers up to two bytes from the most common
+// ciphertext values. Around 2^24 samples per prefix gives reliable results.
+// Passing a cookieLen shorter than the real cookie recovers just its start.
+func CrackRC4Cookie(oracle func([]byte) []byte, cookieLen, samples int) ([]byte, error) {
+	if cookieLen > 32 {
+		return nil, errors.New("cookie must be at most 32 bytes")
+	}
+	if cookieLen > len(oracle(nil)) {
+		return nil, errors.New("cookie is longer than the oracle's")
+	}
+
+	cookie := make([]byte, cookieLen)
+	for prefixLen := 0; prefixLen < 16; prefixLen++ {
+		z16Idx, z32Idx := 15-prefixLen, 31-prefixLen
+		useZ16 := z16Idx < cookieLen
+		useZ32 := z32Idx >= 16 && z32Idx < cookieLen
+		if !useZ16 && !useZ32 {
+			continue
+		}
+
+		hist := rc4Histograms(oracle, bytes.Repeat([]byte{'A'}, prefixLen), samples)
+		if useZ16 {
+			cookie[z16Idx] = mostCommonByte(&hist[0]) ^ rc4Z16Bias
+		}
+		if useZ32 {
+			cookie[z32Idx] = mostCommonByte(&hist[1]) ^ rc4Z32Bias
+		}
+	}
+
+	return cookie, nil
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCBCMAC(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	iv := make([]byte, 16)
+
+	t.Run("MAC is the last CBC block", func(t *testing.T) {
+		msg := []byte("a message that spans more than one block")
+		cText := EncryptCBC(append([]byte{}, msg...), key, iv)
+		assert.Equal(t, cText[len(cText)-16:], CBCMAC(key, iv, msg))
+	})
+
+	t.Run("Does not modify the message", func(t *testing.T) {
+		msg := make([]byte, 20, 64)
+		copy(msg, "twenty byte message!")
+		CBCMAC(key, iv, msg)
+		assert.Equal(t, []byte("twenty byte message!"), msg)
+		assert.Equal(t, make([]byte, 44), msg[20:64])
+	})
+
+	t.Run("Verify", func(t *testing.T) {
+		msg := []byte("from=1&to=2&amount=3")
+		mac := CBCMAC(key, iv, msg)
+		assert.True(t, VerifyCBCMAC(key, iv, msg, mac))
+		assert.False(t, VerifyCBCMAC(key, iv, []byte("from=1&to=2&amount=4"), mac))
+		assert.False(t, VerifyCBCMAC(key, RandomBytes(16), msg, mac))
+	})
+}
+
+func TestChallenge49(t *testing.T) {
+	const victim, attacker = 1, 2
+	key := RandomBytes(aes.BlockSize)
+
+	t.Run("Attacker controlled IV", func(t *testing.T) {
+		server := NewBankServer(key, false)
+		ts := httptest.NewServer(server)
+		defer ts.Close()
+
+		client := NewBankClient(key, attacker)
+		req := client.SignTransfer(attacker, 1000000)
+		require.NoError(t, PostTransfer(ts.URL, req))
+
+		tampered := bytes.Replace(req, []byte("from=2"), []byte("from=1"), 1)
+		assert.Error(t, PostTransfer(ts.URL, tampered))
+
+		forged, err := ForgeTransferWithIV(req, victim, aes.BlockSize)
+		require.NoError(t, err)
+		require.NoError(t, PostTransfer(ts.URL, forged))
+
+		txs := server.Transactions()
+		require.Len(t, txs, 2)
+		assert.Equal(t, Transaction{From: victim, To: attacker, Amount: 1000000}, txs[1])
+	})
+
+	t.Run("Fixed IV", func(t *testing.T) {
+		server := NewBankServer(key, true)
+		ts := httptest.NewServer(server)
+		defer ts.Close()
+
+		victimClient := NewBankClient(key, victim)
+		attackerClient := NewBankClient(key, attacker)
+		attackerReq := attackerClient.SignTxList([]Transaction{
+			{To: 3, Amount: 1},
+			{To: attacker, Amount: 1000000},
+		})
+
+		// The glue block is random, so sniff victim transfers until one
+		// doesn't produce a field separator
+		var forged []byte
+		var err error
+		for amount := 100; amount < 200; amount++ {
+			victimReq := victimClient.SignTxList([]Transaction{{To: 3, Amount: amount}})
+			require.NoError(t, PostTransfer(ts.URL, victimReq))
+			forged, err = ForgeTransferByExtension(victimReq, attackerReq, aes.BlockSize)
+			if err == nil {
+				break
+			}
+		}
+		require.NoError(t, err)
+		require.NoError(t, PostTransfer(ts.URL, forged))
+
+		txs := server.Transactions()
+		assert.Equal(t, Transaction{From: victim, To: attacker, Amount: 1000000}, txs[len(txs)-1])
+	})
+}
+
+func TestChallenge50(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	iv := make([]byte, 16)
+	original := []byte("alert('MZA who was that?');\n")
+	target := CBCMAC(key, iv, original)
+
+	t.Run("Original snippet hash", func(t *testing.T) {
+		assert.Equal(t, "296b8d7cb78a243dda4d0a61d33bbdd1", hex.EncodeToString(target))
+	})
+
+	t.Run("Forged snippet collides", func(t *testing.T) {
+		// Keep adding spaces until the glue block doesn't end the comment
+		prefix := []byte("alert('Ayo, the Wu is back!');//")
+		var forged []byte
+		for {
+			var err error
+			forged, err = ForgeCBCMACPreimage(key, iv, prefix, target)
+			require.NoError(t, err)
+			glue := forged[len(forged)-16:]
+			if !bytes.ContainsAny(glue, "\r\n") {
+				break
+			}
+			prefix = append(prefix, ' ')
+		}
+
+		assert.True(t, bytes.HasPrefix(forged, []byte("alert('Ayo, the Wu is back!');//")))
+		assert.Equal(t, target, CBCMAC(key, iv, forged))
+	})
+
+	t.Run("Prefix not block aligned", func(t *testing.T) {
+		forged, err := ForgeCBCMACPreimage(key, iv, []byte("short"), target)
+		require.NoError(t, err)
+		assert.Equal(t, 32, len(forged))
+		assert.Equal(t, target, CBCMAC(key, iv, forged))
+	})
+}
+
+func TestChallenge51(t *testing.T) {
+	t.Run("Oracle length grows with body", func(t *testing.T) {
+		oracle := NewCompressionOracle(SessionID, CompressionCTR)
+		assert.Less(t, oracle([]byte("a")), oracle(RandomBytes(100)))
+	})
+
+	t.Run("Recover session ID through CTR", func(t *testing.T) {
+		recovered, err := CrackCompressionOracle(NewCompressionOracle(SessionID, CompressionCTR))
+		require.NoError(t, err)
+		assert.Equal(t, SessionID, recovered)
+	})
+
+	t.Run("Recover session ID through CBC", func(t *testing.T) {
+		recovered, err := CrackCompressionOracle(NewCompressionOracle(SessionID, CompressionCBC))
+		require.NoError(t, err)
+		assert.Equal(t, SessionID, recovered)
+	})
+
+	t.Run("Recover random session ID", func(t *testing.T) {
+		sessionID := base64.StdEncoding.EncodeToString(RandomBytes(32))
+		recovered, err := CrackCompressionOracle(NewCompressionOracle(sessionID, CompressionCBC))
+		require.NoError(t, err)
+		assert.Equal(t, sessionID, recovered)
+	})
+}
+
+func TestChallenge55(t *testing.T) {
+	holds := func(q *md4Chain, c md4Condition) bool {
+		mask := uint32(1) << (c.bit - 1)
+		v := q.at(c.step) & mask
+		switch c.kind {
+		case '0':
+			return v == 0
+		case '1':
+			return v != 0
+		case '=':
+			return v == q.at(c.ref)&mask
+		default:
+			return v != q.at(c.ref)&mask
+		}
+	}
+
+	t.Run("Single-step modification satisfies round 1 conditions", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			_, x := wangSingleStep(MD4BlockWords(RandomBytes(64)))
+			q := md4ChainFor(&x)
+			for _, c := range wangConditions {
+				if c.step < 16 {
+					assert.True(t, holds(q, c), "step %d bit %d", c.step, c.bit)
+				}
+			}
+		}
+	})
+
+	t.Run("Multi-step modification satisfies a5 and d5 conditions", func(t *testing.T) {
+		for i := 0; i < 100; i++ {
+			x := WangModify(MD4BlockWords(RandomBytes(64)))
+			q := md4ChainFor(&x)
+			for _, c := range wangConditions {
+				if c.step == 16 || c.step == 17 {
+					assert.True(t, holds(q, c), "step %d bit %d", c.step, c.bit)
+				}
+			}
+		}
+	})
+
+	t.Run("Find collision", func(t *testing.T) {
+		m1, m2, attempts := FindMD4Collision()
+		assert.NotEqual(t, m1, m2)
+		assert.Equal(t, MD4Sum(m1), MD4Sum(m2))
+		t.Logf("Collision after %d attempts", attempts)
+	})
+}
+
+func BenchmarkMD4Collision(b *testing.B) {
+	total := 0
+	for i := 0; i < b.N; i++ {
+		_, _, attempts := FindMD4Collision()
+		total += attempts
+	}
+	b.ReportMetric(float64(total)/float64(b.N), "attempts/collision")
+}
+
+func TestChallenge56(t *testing.T) {
+	oracle := NewRC4CookieOracle(RC4Cookie)
+
+	t.Run("Oracle appends cookie", func(t *testing.T) {
+		cText := oracle([]byte("/"))
+		assert.Equal(t, len(RC4Cookie)+1, len(cText))
+		assert.NotEqual(t, cText, oracle([]byte("/")))
+	})
+
+	t.Run("Recover start of cookie from Z16 bias", func(t *testing.T) {
+		// Recovering every byte needs about 2^24 samples for each of 16
+		// prefix lengths, which is too slow to run here, so just take the
+		// first byte
+		cookie, err := CrackRC4Cookie(oracle, 1, 1<<23)
+		require.NoError(t, err)
+		assert.Equal(t, RC4Cookie[:1], cookie)
+	})
+
+	t.Run("Recover whole cookie from planted biases", func(t *testing.T) {
+		// A stand-in for RC4 whose keystream is random except that bytes
+		// 16 and 32 take their biased values a quarter of the time, so
+		// both the Z16 and Z32 paths run with few samples
+		stub := func(request []byte) []byte {
+			pText := append(append([]byte{}, request...), RC4Cookie...)
+			stream := RandomBytes(len(pText))
+			if stream[0]%4 == 0 {
+				stream[15] = rc4Z16Bias
+			}
+			if len(stream) > 31 && stream[1]%4 == 0 {
+				stream[31] = rc4Z32Bias
+			}
+			return FixedXor(pText, stream)
+		}
+
+		cookie, err := CrackRC4Cookie(stub, len(RC4Cookie), 1<<12)
+		require.NoError(t, err)
+		assert.Equal(t, RC4Cookie, cookie)
+	})
+
+	t.Run("Recover whole cookie from real RC4", func(t *testing.T) {
+		if os.Getenv("CRYPTOPALS_LONG") == "" {
+			t.Skip("2^24 samples for each of 16 prefixes, set CRYPTOPALS_LONG=1 and raise -timeout to run")
+		}
+		cookie, err := CrackRC4Cookie(oracle, len(RC4Cookie), 1<<24)
+		require.NoError(t, err)
+		assert.Equal(t, RC4Cookie, cookie)
+	})
+
+	t.Run("Cookie too long", func(t *testing.T) {
+		_, err := CrackRC4Cookie(oracle, 33, 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("Cookie longer than the oracle's", func(t *testing.T) {
+		short := NewRC4CookieOracle(RC4Cookie[:10])
+		_, err := CrackRC4Cookie(short, 20, 1)
+		assert.Error(t, err)
+	})
+}